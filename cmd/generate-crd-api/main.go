@@ -9,15 +9,36 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/bakito/crd-gen/internal/config"
 	"github.com/bakito/crd-gen/internal/openapi"
 	"github.com/bakito/crd-gen/internal/render"
 )
 
 var (
-	crds     []string
-	target   string
-	version  string
-	pointers bool
+	crds        []string
+	target      string
+	version     string
+	versions    []string
+	pointers    bool
+	pointerMode string
+	naming      string
+	celRules    bool
+	conversion  bool
+	applyConfig bool
+	clients     bool
+	templateDir string
+	pkgName     string
+	layout      string
+	typesFile   string
+	check       bool
+	importPath  string
+	hubVersion  string
+	kinds       []string
+	groups      []string
+	helmRender  bool
+	helmValues  []string
+	knownTypes  bool
+	configFile  string
 
 	clientConfig clientcmd.ClientConfig
 )
@@ -28,12 +49,64 @@ func newRootCmd() *cobra.Command {
 		Short: "Generate Go API code from CRD files",
 		RunE:  run,
 	}
-	cmd.Flags().StringSliceVar(&crds, "crd", nil, "CRD file to process")
+	cmd.Flags().StringSliceVar(&crds, "crd", nil,
+		"CRD file, directory, glob pattern or Helm chart (directory or .tgz) to process; "+
+			"multi-document files are supported")
+	cmd.Flags().StringSliceVar(&kinds, "kind", nil,
+		"The kinds of the CRDs to generate; If not defined, all kinds are generated")
+	cmd.Flags().StringSliceVar(&groups, "group", nil,
+		"The API groups of the CRDs to generate; If not defined, all groups are generated")
 	cmd.Flags().StringVar(&target, "target", "", "The target directory to copyFile the files to")
 	cmd.Flags().BoolVar(&pointers, "pointer", false, "If enabled, struct variables are generated as pointers")
+	cmd.Flags().StringVar(&pointerMode, "pointer-mode", "",
+		`Define which struct variables are generated as pointers: "all" or "optional" (optional scalar and struct fields)`)
+	cmd.Flags().StringVar(&naming, "naming", "",
+		`Define how structs and enum types are named: "path" (kind and full schema path) or "kind" (kind and field); `+
+			`If not defined, the shortest unique name is used`)
+	cmd.Flags().BoolVar(&celRules, "cel-validation", false,
+		"If enabled, a ValidateCEL method is generated evaluating the x-kubernetes-validations rules offline")
+	cmd.Flags().BoolVar(&conversion, "conversion", false,
+		"If enabled, conversion functions between the generated versions of a kind are generated")
+	cmd.Flags().BoolVar(&applyConfig, "apply-configurations", false,
+		"If enabled, server-side apply configurations are generated into the applyconfiguration directory")
+	cmd.Flags().BoolVar(&clients, "clients", false,
+		"If enabled, a typed clientset, listers and informers are generated into the clientset, listers and "+
+			"informers directories")
+	cmd.Flags().StringVar(&hubVersion, "hub", "",
+		"The hub version of the generated conversions; If not defined, the storage version is used")
+	cmd.Flags().BoolVar(&knownTypes, "known-types", false,
+		"If enabled, schemas matching well known Kubernetes types use the upstream type instead of a generated struct")
+	cmd.Flags().StringVar(&pkgName, "package", "",
+		"The go package name of the generated packages; If not defined, the version is used")
+	cmd.Flags().StringVar(&layout, "layout", "",
+		`Define the directories of the generated packages: "group-version" (<group>/<version>) `+
+			`or "flat" (the target directory); If not defined, <version> is used for a single group `+
+			`and <group>/<version> for several groups`)
+	cmd.Flags().StringVar(&typesFile, "types-file", render.DefaultTypesFile,
+		`The file name pattern of the types files with the placeholders {kind}, {Kind} and {version}; `+
+			`Kinds with the same file name are combined, e.g. "types.go" for a single file`)
+	cmd.Flags().BoolVar(&check, "check", false,
+		"If enabled, the generated files are compared with the files in the target directory without writing; "+
+			"the differences are printed as unified diff and the command fails if the files are not up to date")
+	cmd.Flags().StringVar(&importPath, "import-path", "",
+		"The go import path of the target directory, used to import the types and hub packages; "+
+			"If not defined, it is evaluated from the go module of the target directory")
+	cmd.Flags().StringVar(&templateDir, "template-dir", "",
+		"The directory of templates replacing the built-in types.go.tpl and group_version_into.go.tpl, "+
+			"and of additional templates in its kind and group directories")
+	cmd.Flags().StringVar(&configFile, "config", "",
+		"The configuration file defining type overrides, initialisms and field names")
+	cmd.Flags().BoolVar(&helmRender, "helm-template", false,
+		"If enabled, the templates of Helm chart inputs are rendered with helm to find templated CRDs")
+	cmd.Flags().StringSliceVar(&helmValues, "helm-values", nil,
+		"Values file to render the templates of Helm chart inputs with; implies --helm-template")
 	cmd.Flags().
-		StringVar(&version, "version", "", "The version to select from the CRD; If not defined, the first version is used")
+		StringVar(&version, "version", "", "The version to select from the CRD; If not defined, the storage version is used")
+	cmd.Flags().StringSliceVar(&versions, "versions", nil,
+		`The versions to select from the CRD: "all", "served", "storage" or a list of version names; `+
+			`a package is generated per version`)
 	_ = cmd.MarkFlagRequired("target")
+	cmd.MarkFlagsMutuallyExclusive("pointer", "pointer-mode")
 	return cmd
 }
 
@@ -55,13 +128,74 @@ func run(cmd *cobra.Command, _ []string) error {
 		return errors.New("at least one CRD must be defined")
 	}
 
-	slog.With("target", target, "crd", crds, "version", version).InfoContext(cmd.Context(), "generate-crd-api")
+	mode := openapi.PointerMode(pointerMode)
+	if pointers {
+		mode = openapi.PointerAll
+	}
+	switch mode {
+	case openapi.PointerNone, openapi.PointerAll, openapi.PointerOptional:
+	default:
+		return fmt.Errorf("invalid pointer mode %q", pointerMode)
+	}
+
+	if version != "" {
+		versions = append(versions, version)
+	}
+
+	slog.With("target", target, "crd", crds, "versions", versions).InfoContext(cmd.Context(), "generate-crd-api")
 	defer fmt.Println()
 
-	resources, success := openapi.Parse(cmd.Context(), clientConfig, crds, version, pointers)
-	if !success {
-		return errors.New("failed to parse CRDs")
+	parseOpts := openapi.Options{
+		Versions:      versions,
+		Pointers:      mode,
+		Kinds:         kinds,
+		Groups:        groups,
+		HelmTemplates: helmRender,
+		HelmValues:    helmValues,
+		ClientConfig:  clientConfig,
+		Naming:        openapi.NamingStrategy(naming),
+	}
+	if knownTypes {
+		parseOpts.KnownTypes = openapi.DefaultKnownTypes()
+	}
+	if configFile != "" {
+		cfg, err := config.Load(configFile)
+		if err != nil {
+			return err
+		}
+		parseOpts.TypeOverrides = cfg.TypeOverrides
+		parseOpts.Initialisms = cfg.Initialisms
+		parseOpts.FieldNames = cfg.FieldNames
+		for _, ref := range cfg.KnownTypes {
+			kt, err := ref.Resolve()
+			if err != nil {
+				return err
+			}
+			parseOpts.KnownTypes = append(parseOpts.KnownTypes, kt)
+		}
+	}
+
+	resources, err := openapi.Parse(cmd.Context(), crds, parseOpts)
+	if err != nil {
+		return fmt.Errorf("failed to parse CRDs:\n%w", err)
 	}
 
-	return render.WriteCrdFiles(cmd.Context(), resources, target)
+	renderOpts := render.Options{
+		CELValidation:       celRules,
+		Conversion:          conversion,
+		HubVersion:          hubVersion,
+		ApplyConfigurations: applyConfig,
+		Clients:             clients,
+		TemplateDir:         templateDir,
+		Package:             pkgName,
+		Layout:              render.Layout(layout),
+		TypesFile:           typesFile,
+		ImportPath:          importPath,
+	}
+	if check {
+		// stale files are no usage error
+		cmd.SilenceUsage = true
+		return render.CheckCrdFiles(cmd.Context(), resources, target, renderOpts, cmd.OutOrStdout())
+	}
+	return render.WriteCrdFiles(cmd.Context(), resources, target, renderOpts)
 }
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"flag"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/bakito/crd-gen/internal/render"
 )
 
 var update = flag.Bool("update", false, "update golden files")
@@ -19,14 +24,18 @@ func TestGenerateCrdApiE2E(t *testing.T) {
 	wd, err := os.Getwd()
 	require.NoError(t, err)
 	testdata := filepath.Join(wd, "..", "..", "testdata")
+	chartArchive := packageChart(t, filepath.Join(testdata, "helm", "crd-gen-chart"), tempDir)
 
 	testCases := []struct {
-		name               string
-		args               []string
-		wantErrMsg         string
-		expectedFiles      []string
-		fileContentChecks  map[string][]string
-		expectedFileGolden map[string]string
+		name                 string
+		args                 []string
+		wantErrMsg           string
+		expectedFiles        []string
+		expectedMissingFiles []string
+		goModule             string
+		needsHelm            bool
+		fileContentChecks    map[string][]string
+		expectedFileGolden   map[string]string
 	}{
 		{
 			name:       "no_crds_defined",
@@ -50,6 +59,8 @@ func TestGenerateCrdApiE2E(t *testing.T) {
 					"package v1beta2",
 					"type Tenant struct {",
 					"Spec TenantSpec",
+					"Default map[string]resource.Quantity `json:\"default,omitempty\"`",
+					`"k8s.io/apimachinery/pkg/api/resource"`,
 				},
 				"v1beta2/group_version_info.go": {
 					`GroupVersion = schema.GroupVersion{Group: "capsule.clastix.io", Version: "v1beta2"}`,
@@ -74,7 +85,24 @@ func TestGenerateCrdApiE2E(t *testing.T) {
 				"--crd", filepath.Join(testdata, "capsule.clastix.io_tenants.yaml"),
 				"--crd", filepath.Join(testdata, "applications.argoproj.io.yaml"),
 			},
-			wantErrMsg: "failed to parse CRDs",
+			expectedFiles: []string{
+				"capsule/v1beta2/group_version_info.go",
+				"capsule/v1beta2/types_tenant.go",
+				"argoproj/v1alpha1/group_version_info.go",
+				"argoproj/v1alpha1/types_application.go",
+			},
+			expectedMissingFiles: []string{
+				"v1beta2/group_version_info.go",
+				"v1alpha1/group_version_info.go",
+			},
+			fileContentChecks: map[string][]string{
+				"capsule/v1beta2/group_version_info.go": {
+					`GroupVersion = schema.GroupVersion{Group: "capsule.clastix.io", Version: "v1beta2"}`,
+				},
+				"argoproj/v1alpha1/group_version_info.go": {
+					`GroupVersion = schema.GroupVersion{Group: "argoproj.io", Version: "v1alpha1"}`,
+				},
+			},
 		},
 		{
 			name: "with_version_not_storage",
@@ -82,8 +110,243 @@ func TestGenerateCrdApiE2E(t *testing.T) {
 				"--crd", filepath.Join(testdata, "capsule.clastix.io_tenants.yaml"),
 				"--version", "v1beta1",
 			},
+			expectedFiles: []string{
+				"v1beta1/group_version_info.go",
+				"v1beta1/types_tenant.go",
+			},
+			fileContentChecks: map[string][]string{
+				"v1beta1/group_version_info.go": {
+					`GroupVersion = schema.GroupVersion{Group: "capsule.clastix.io", Version: "v1beta1"}`,
+				},
+			},
+		},
+		{
+			name: "with_unknown_version",
+			args: []string{
+				"--crd", filepath.Join(testdata, "capsule.clastix.io_tenants.yaml"),
+				"--version", "v1",
+			},
+			wantErrMsg: `failed to parse CRDs`,
+		},
+		{
+			name: "multi_document_file",
+			args: []string{
+				"--crd", filepath.Join(testdata, "bundle", "crds.yaml"),
+			},
+			expectedFiles: []string{
+				"v1/group_version_info.go",
+				"v1/types_widget.go",
+				"v1/types_gadget.go",
+			},
+			expectedMissingFiles: []string{
+				"v1/types_namespace.go",
+				"v1/types_configmap.go",
+			},
+			fileContentChecks: map[string][]string{
+				"v1/group_version_info.go": {
+					`GroupVersion = schema.GroupVersion{Group: "bundle.crd-gen", Version: "v1"}`,
+					"&Gadget{}, &GadgetList{}",
+					"&Widget{}, &WidgetList{}",
+				},
+			},
+		},
+		{
+			name: "directory",
+			args: []string{
+				"--crd", filepath.Join(testdata, "bundle"),
+			},
+			expectedFiles: []string{
+				"v1/types_widget.go",
+				"v1/types_gadget.go",
+				"v1/types_gizmo.go",
+			},
+		},
+		{
+			name: "directory_with_kind_filter",
+			args: []string{
+				"--crd", filepath.Join(testdata, "bundle"),
+				"--kind", "gizmo,Widget",
+			},
+			expectedFiles: []string{
+				"v1/types_widget.go",
+				"v1/types_gizmo.go",
+			},
+			expectedMissingFiles: []string{
+				"v1/types_gadget.go",
+			},
+		},
+		{
+			name: "glob_with_group_filter",
+			args: []string{
+				"--crd", filepath.Join(testdata, "*.yaml"),
+				"--group", "cert-manager.io",
+			},
+			expectedFiles: []string{
+				"v1/types_certificate.go",
+				"v1/types_certificaterequest.go",
+				"v1/types_clusterissuer.go",
+			},
+			expectedMissingFiles: []string{
+				"v1/types_alltypes.go",
+				"v1beta2/types_tenant.go",
+			},
+		},
+		{
+			name: "glob_without_match",
+			args: []string{
+				"--crd", filepath.Join(testdata, "*.json"),
+			},
+			wantErrMsg: `failed to parse CRDs`,
+		},
+		{
+			name: "filter_without_match",
+			args: []string{
+				"--crd", filepath.Join(testdata, "bundle"),
+				"--group", "unknown.crd-gen",
+			},
 			wantErrMsg: `failed to parse CRDs`,
 		},
+		{
+			name: "helm_chart_directory",
+			args: []string{
+				"--crd", filepath.Join(testdata, "helm", "crd-gen-chart"),
+			},
+			expectedFiles: []string{
+				"v1/group_version_info.go",
+				"v1/types_widget.go",
+				"v1/types_gadget.go",
+			},
+			expectedMissingFiles: []string{
+				"v1/types_gizmo.go",
+			},
+		},
+		{
+			name: "helm_chart_archive",
+			args: []string{
+				"--crd", chartArchive,
+				"--kind", "Widget",
+			},
+			expectedFiles: []string{
+				"v1/types_widget.go",
+			},
+			expectedMissingFiles: []string{
+				"v1/types_gadget.go",
+				"v1/types_gizmo.go",
+			},
+		},
+		{
+			name: "helm_chart_templates",
+			args: []string{
+				"--crd", filepath.Join(testdata, "helm", "crd-gen-chart"),
+				"--helm-values", filepath.Join(testdata, "helm", "values-install-crds.yaml"),
+			},
+			needsHelm: true,
+			expectedFiles: []string{
+				"v1/types_widget.go",
+				"v1/types_gadget.go",
+				"v1/types_gizmo.go",
+			},
+		},
+		{
+			name: "invalid_crds",
+			args: []string{
+				"--crd", filepath.Join(testdata, "invalid"),
+				"--versions", "all",
+			},
+			wantErrMsg: `path "spec.nested.unknownType": unsupported schema type "decimal"`,
+		},
+		{
+			name: "invalid_crds_collects_all_errors",
+			args: []string{
+				"--crd", filepath.Join(testdata, "invalid"),
+				"--versions", "all",
+			},
+			wantErrMsg: `crd "brokens.testing.crd-gen", version "v1alpha1": no openAPIV3Schema defined`,
+		},
+		{
+			name: "known_types",
+			args: []string{
+				"--crd", filepath.Join(testdata, "clusterissuers.cert-manager.io.yaml"),
+				"--known-types",
+			},
+			fileContentChecks: map[string][]string{
+				"v1/types_clusterissuer.go": {
+					`corev1 "k8s.io/api/core/v1"`,
+					"Tolerations []corev1.Toleration `json:\"tolerations,omitempty\"`",
+					"Affinity corev1.Affinity `json:\"affinity,omitempty\"`",
+					"PrivateKeySecretRef corev1.SecretKeySelector `json:\"privateKeySecretRef\"`",
+				},
+			},
+		},
+		{
+			name: "known_types_config",
+			args: []string{
+				"--crd", filepath.Join(testdata, "clusterissuers.cert-manager.io.yaml"),
+				"--config", filepath.Join(testdata, "config", "known-types.yaml"),
+			},
+			fileContentChecks: map[string][]string{
+				"v1/types_clusterissuer.go": {
+					"Tolerations []corev1.Toleration `json:\"tolerations,omitempty\"`",
+					"Affinity Affinity `json:\"affinity,omitempty\"`",
+				},
+			},
+		},
+		{
+			name: "without_known_types",
+			args: []string{
+				"--crd", filepath.Join(testdata, "clusterissuers.cert-manager.io.yaml"),
+			},
+			fileContentChecks: map[string][]string{
+				"v1/types_clusterissuer.go": {
+					"Tolerations []Tolerations `json:\"tolerations,omitempty\"`",
+				},
+			},
+		},
+		{
+			name: "with_all_versions",
+			args: []string{
+				"--crd", filepath.Join(testdata, "external-secrets.io_clustersecretstores.yaml"),
+				"--versions", "all",
+			},
+			expectedFiles: []string{
+				"v1/group_version_info.go",
+				"v1/types_clustersecretstore.go",
+				"v1beta1/group_version_info.go",
+				"v1beta1/types_clustersecretstore.go",
+			},
+			fileContentChecks: map[string][]string{
+				"v1/group_version_info.go": {
+					`GroupVersion = schema.GroupVersion{Group: "external-secrets.io", Version: "v1"}`,
+				},
+				"v1beta1/types_clustersecretstore.go": {"package v1beta1"},
+			},
+		},
+		{
+			name: "with_served_versions_only",
+			args: []string{
+				"--crd", filepath.Join(testdata, "capsule.clastix.io_tenants.yaml"),
+				"--versions", "served",
+			},
+			expectedFiles: []string{
+				"v1beta1/group_version_info.go",
+				"v1beta1/types_tenant.go",
+				"v1beta2/group_version_info.go",
+				"v1beta2/types_tenant.go",
+			},
+		},
+		{
+			name: "with_explicit_versions",
+			args: []string{
+				"--crd", filepath.Join(testdata, "external-secrets.io_clustersecretstores.yaml"),
+				"--versions", "v1beta1",
+			},
+			expectedFiles: []string{
+				"v1beta1/types_clustersecretstore.go",
+			},
+			expectedMissingFiles: []string{
+				"v1/types_clustersecretstore.go",
+			},
+		},
 		{
 			name: "with_pointers",
 			args: []string{
@@ -128,7 +391,60 @@ func TestGenerateCrdApiE2E(t *testing.T) {
 			expectedFileGolden: map[string]string{
 				"v1/group_version_info.go": filepath.Join(testdata, "expected", "all-cases", "group_version_info.go.txt"),
 				"v1/types_allcase.go":      filepath.Join(testdata, "expected", "all-cases", "types_allcase.go.txt"),
+				"v1/zz_generated.defaults.go": filepath.Join(
+					testdata, "expected", "all-cases", "zz_generated.defaults.go.txt",
+				),
+				"v1/zz_generated.deepcopy.go": filepath.Join(
+					testdata, "expected", "all-cases", "zz_generated.deepcopy.go.txt",
+				),
+			},
+		},
+		{
+			name: "type_overrides",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--config", filepath.Join(testdata, "config", "type-overrides.yaml"),
+				"--pointer-mode", "optional",
+			},
+			expectedFiles: []string{
+				"v1/types_allcase.go",
+			},
+			fileContentChecks: map[string][]string{
+				"v1/types_allcase.go": {
+					`"encoding/json"`,
+					`"k8s.io/apimachinery/pkg/runtime"`,
+					"ObjectField json.RawMessage `json:\"objectField\"`",
+					"ArrayOfObjects []runtime.RawExtension `json:\"arrayOfObjects,omitempty\"`",
+					"StringField string `json:\"stringField\"`",
+				},
+			},
+		},
+		{
+			name: "naming_config",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--config", filepath.Join(testdata, "config", "naming.yaml"),
+			},
+			expectedFiles: []string{
+				"v1/types_allcase.go",
+			},
+			fileContentChecks: map[string][]string{
+				"v1/types_allcase.go": {
+					"ENUMField ENUMField `json:\"enumField,omitempty\"`",
+					"ENUMFieldValue1 ENUMField = \"Value1\"",
+					"Name string `json:\"stringField\"`",
+					"Text string `json:\"nestedString\"`",
+					"UUIDField string `json:\"uuidField,omitempty\"`",
+				},
+			},
+		},
+		{
+			name: "invalid_config",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--config", filepath.Join(testdata, "config", "invalid.yaml"),
 			},
+			wantErrMsg: `typeOverrides[0]: invalid type override: the import of type "json.RawMessage" must be defined`,
 		},
 		{
 			name: "all_cases_pointers",
@@ -138,17 +454,312 @@ func TestGenerateCrdApiE2E(t *testing.T) {
 				"v1/types_allcase.go":      filepath.Join(testdata, "expected", "all-cases", "types_allcase_pointers.go.txt"),
 			},
 		},
+		{
+			name: "all_cases_optional_pointers",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--pointer-mode", "optional",
+			},
+			expectedFileGolden: map[string]string{
+				"v1/types_allcase.go": filepath.Join(testdata, "expected", "all-cases", "types_allcase_optional_pointers.go.txt"),
+				"v1/zz_generated.defaults.go": filepath.Join(
+					testdata, "expected", "all-cases", "zz_generated.defaults_optional_pointers.go.txt",
+				),
+			},
+		},
+		{
+			name: "cel_validation",
+			args: []string{
+				"--crd", filepath.Join(testdata, "cel-validations.testing.crd-gen.yaml"),
+				"--cel-validation",
+			},
+			expectedFileGolden: map[string]string{
+				"v1/types_celvalidation.go": filepath.Join(
+					testdata, "expected", "cel-validations", "types_celvalidation.go.txt",
+				),
+				"v1/zz_generated.cel.go": filepath.Join(testdata, "expected", "cel-validations", "zz_generated.cel.go.txt"),
+			},
+		},
+		{
+			name: "conversion",
+			args: []string{
+				"--crd", filepath.Join(testdata, "conversion.testing.crd-gen.yaml"),
+				"--versions", "all",
+				"--conversion",
+				"--pointer-mode", "optional",
+			},
+			goModule: "example.com/conversion",
+			expectedFileGolden: map[string]string{
+				"v1/zz_generated.conversion.go": filepath.Join(
+					testdata, "expected", "conversion", "v1", "zz_generated.conversion.go.txt",
+				),
+				"v1alpha1/zz_generated.conversion.go": filepath.Join(
+					testdata, "expected", "conversion", "v1alpha1", "zz_generated.conversion.go.txt",
+				),
+			},
+		},
+		{
+			name: "apply_configurations",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--apply-configurations",
+			},
+			goModule: "example.com/allcases",
+			expectedFileGolden: map[string]string{
+				"applyconfiguration/v1/allcase.go": filepath.Join(
+					testdata, "expected", "apply-configurations", "allcase.go.txt",
+				),
+				"applyconfiguration/v1/internal.go": filepath.Join(
+					testdata, "expected", "apply-configurations", "internal.go.txt",
+				),
+			},
+		},
+		{
+			name: "clients",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--apply-configurations",
+				"--clients",
+			},
+			goModule: "example.com/allcases",
+			expectedFileGolden: map[string]string{
+				"clientset/versioned/typed/testing/v1/allcase.go": filepath.Join(
+					testdata, "expected", "clients", "typed_allcase.go.txt",
+				),
+				"listers/testing/v1/allcase.go": filepath.Join(
+					testdata, "expected", "clients", "lister_allcase.go.txt",
+				),
+				"informers/externalversions/testing/v1/allcase.go": filepath.Join(
+					testdata, "expected", "clients", "informer_allcase.go.txt",
+				),
+			},
+		},
+		{
+			name: "template_dir",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--template-dir", filepath.Join(testdata, "templates"),
+			},
+			expectedFiles: []string{
+				"v1/types_allcase.go",
+			},
+			expectedFileGolden: map[string]string{
+				"v1/group_version_info.go": filepath.Join(
+					testdata, "expected", "templates", "group_version_info.go.txt",
+				),
+				"v1/accessors_allcase.go": filepath.Join(
+					testdata, "expected", "templates", "accessors_allcase.go.txt",
+				),
+				"v1/kinds.go": filepath.Join(
+					testdata, "expected", "templates", "kinds.go.txt",
+				),
+			},
+		},
+		{
+			name: "template_dir_unknown_template",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--template-dir", filepath.Join(testdata, "invalid-templates"),
+			},
+			wantErrMsg: `unknown template`,
+		},
+		{
+			name: "flat_layout_single_types_file",
+			args: []string{
+				"--crd", testdata,
+				"--group", "testing.crd-gen",
+				"--layout", "flat",
+				"--package", "testing",
+				"--types-file", "types.go",
+			},
+			expectedFiles: []string{
+				"types.go",
+				"group_version_info.go",
+				"zz_generated.deepcopy.go",
+			},
+			expectedMissingFiles: []string{
+				"v1/types_allcase.go",
+				"types_allcase.go",
+			},
+			fileContentChecks: map[string][]string{
+				"types.go": {
+					"package testing",
+					"type AllCase struct {",
+					"type CelValidation struct {",
+					"type NoListKind struct {",
+				},
+				"group_version_info.go": {
+					"package testing",
+				},
+			},
+		},
+		{
+			name: "group_version_layout_types_file_pattern",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--layout", "group-version",
+				"--types-file", "zz_{Kind}_{version}.go",
+			},
+			expectedFiles: []string{
+				"testing/v1/zz_AllCase_v1.go",
+				"testing/v1/group_version_info.go",
+			},
+		},
+		{
+			name: "flat_layout_several_packages",
+			args: []string{
+				"--crd", testdata,
+				"--layout", "flat",
+			},
+			wantErrMsg: `invalid layout "flat"`,
+		},
+		{
+			name: "invalid_package_name",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--package", "my-api",
+			},
+			wantErrMsg: `invalid package name "my-api"`,
+		},
+		{
+			name: "conversion_without_go_module",
+			args: []string{
+				"--crd", filepath.Join(testdata, "conversion.testing.crd-gen.yaml"),
+				"--versions", "all",
+				"--conversion",
+			},
+			wantErrMsg: "could not find a go.mod file",
+		},
+		{
+			name: "conversion_with_import_path",
+			args: []string{
+				"--crd", filepath.Join(testdata, "conversion.testing.crd-gen.yaml"),
+				"--versions", "all",
+				"--conversion",
+				"--import-path", "example.com/conversion/api",
+			},
+			fileContentChecks: map[string][]string{
+				"v1alpha1/zz_generated.conversion.go": {`hub "example.com/conversion/api/v1"`},
+			},
+		},
+		{
+			name: "apply_configurations_without_go_module",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--apply-configurations",
+			},
+			wantErrMsg: "could not find a go.mod file",
+		},
+		{
+			name: "apply_configurations_with_import_path",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--apply-configurations",
+				"--import-path", "example.com/allcases/api",
+			},
+			fileContentChecks: map[string][]string{
+				"applyconfiguration/v1/allcase.go": {`api "example.com/allcases/api/v1"`},
+			},
+		},
+		{
+			name: "clients_with_import_path",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--apply-configurations",
+				"--clients",
+				"--import-path", "example.com/allcases/api",
+			},
+			fileContentChecks: map[string][]string{
+				"clientset/versioned/typed/testing/v1/allcase.go": {
+					`applyconfigurationtestingv1 "example.com/allcases/api/applyconfiguration/v1"`,
+					`"example.com/allcases/api/clientset/versioned/scheme"`,
+					`testingv1 "example.com/allcases/api/v1"`,
+				},
+			},
+		},
+		{
+			name: "apply_configurations_subresources",
+			args: []string{
+				"--crd", filepath.Join(testdata, "subresources.testing.crd-gen.yaml"),
+				"--apply-configurations",
+			},
+			goModule: "example.com/scalables",
+			fileContentChecks: map[string][]string{
+				"applyconfiguration/v1/scalable.go": {
+					"func ExtractScalable(scalable *api.Scalable, fieldManager string) (*ScalableApplyConfiguration, error) {",
+					"func ExtractScalableStatus(scalable *api.Scalable, fieldManager string) (*ScalableApplyConfiguration, error) {",
+					`return ExtractScalableFrom(scalable, fieldManager, "status")`,
+					"func ExtractScalableScale(scalable *api.Scalable, fieldManager string) (*ScalableApplyConfiguration, error) {",
+					`return ExtractScalableFrom(scalable, fieldManager, "scale")`,
+				},
+			},
+		},
+		{
+			name: "check_not_generated",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--check",
+			},
+			wantErrMsg: `generated files are not up to date: 4 files differ`,
+		},
+		{
+			name: "invalid_pointer_mode",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--pointer-mode", "some",
+			},
+			wantErrMsg: `invalid pointer mode "some"`,
+		},
+		{
+			name: "pointer_and_pointer_mode",
+			args: []string{
+				"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+				"--pointer",
+				"--pointer-mode", "optional",
+			},
+			wantErrMsg: "if any flags in the group [pointer pointer-mode] are set none of the others can be",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			if tc.needsHelm {
+				if _, err := exec.LookPath("helm"); err != nil {
+					t.Skip("helm is not installed")
+				}
+			}
+
 			crds = nil
 			target = ""
 			version = ""
+			versions = nil
 			pointers = false
+			pointerMode = ""
+			naming = ""
+			celRules = false
+			conversion = false
+			applyConfig = false
+			clients = false
+			templateDir = ""
+			pkgName = ""
+			layout = ""
+			typesFile = render.DefaultTypesFile
+			check = false
+			importPath = ""
+			hubVersion = ""
+			kinds = nil
+			groups = nil
+			helmRender = false
+			helmValues = nil
+			knownTypes = false
+			configFile = ""
 
 			targetDir := filepath.Join(tempDir, tc.name)
 			require.NoError(t, os.Mkdir(targetDir, 0o755))
+			if tc.goModule != "" {
+				goMod := []byte("module " + tc.goModule + "\n")
+				require.NoError(t, os.WriteFile(filepath.Join(targetDir, "go.mod"), goMod, 0o644))
+			}
 
 			rootCmd := newRootCmd()
 			b := new(bytes.Buffer)
@@ -175,6 +786,10 @@ func TestGenerateCrdApiE2E(t *testing.T) {
 					}
 				}
 
+				for _, file := range tc.expectedMissingFiles {
+					assert.NoFileExists(t, filepath.Join(targetDir, file))
+				}
+
 				if len(tc.fileContentChecks) > 0 {
 					for file, contents := range tc.fileContentChecks {
 						data, err := os.ReadFile(filepath.Join(targetDir, file))
@@ -211,3 +826,147 @@ func TestGenerateCrdApiE2E(t *testing.T) {
 		})
 	}
 }
+
+// packageChart packages a chart directory into a .tgz archive like helm package does.
+func packageChart(t *testing.T, chartDir, targetDir string) string {
+	t.Helper()
+
+	archive := filepath.Join(targetDir, filepath.Base(chartDir)+"-0.1.0.tgz")
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.AddFS(os.DirFS(filepath.Dir(chartDir))))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return archive
+}
+
+// TestGenerateCrdApiCompiles generates a tree into the module of this repository and vets it,
+// to verify the generated packages compile against each other. The deep copy and defaulting of the types
+// are tested in the generated package.
+func TestGenerateCrdApiCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go vet of the generated code in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go binary not found")
+	}
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	testdata := filepath.Join(wd, "..", "..", "testdata")
+
+	// the testdata directory is part of the module, but excluded from ./... patterns
+	moduleDir := filepath.Join(wd, "testdata")
+	require.NoError(t, os.MkdirAll(moduleDir, 0o755))
+	targetDir, err := os.MkdirTemp(moduleDir, "compile-")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(targetDir)
+		_ = os.Remove(moduleDir)
+	})
+
+	rootCmd := newRootCmd()
+	b := new(bytes.Buffer)
+	rootCmd.SetOut(b)
+	rootCmd.SetErr(b)
+	rootCmd.SetArgs([]string{
+		"--crd", filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"),
+		"--crd", filepath.Join(testdata, "subresources.testing.crd-gen.yaml"),
+		"--crd", filepath.Join(testdata, "cel-validations.testing.crd-gen.yaml"),
+		"--config", filepath.Join(testdata, "config", "type-overrides.yaml"),
+		"--cel-validation",
+		"--apply-configurations",
+		"--clients",
+		"--target", targetDir,
+	})
+	require.NoError(t, rootCmd.Execute())
+	require.NoError(t, os.WriteFile(filepath.Join(targetDir, "v1", "generated_test.go"), []byte(generatedTest), 0o644))
+
+	rel, err := filepath.Rel(wd, targetDir)
+	require.NoError(t, err)
+	out, err := exec.Command(goBin, "vet", "./"+filepath.ToSlash(rel)+"/...").CombinedOutput()
+	require.NoError(t, err, string(out))
+	out, err = exec.Command(goBin, "test", "./"+filepath.ToSlash(rel)+"/v1").CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// generatedTest verifies that fields of overridden types without DeepCopyInto method are deep copied,
+// that defaults neither replace explicit zero values nor create unset parents, and that only transition rules
+// referring to the oldSelf variable are skipped by the CEL validation.
+const generatedTest = `package v1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeepCopy(t *testing.T) {
+	in := &AllCase{}
+	in.Spec.ObjectField = []byte("[1]")
+
+	out := in.DeepCopy()
+	out.Spec.ObjectField[1] = '2'
+
+	if string(in.Spec.ObjectField) != "[1]" {
+		t.Errorf("the json.RawMessage of the original was modified: %s", in.Spec.ObjectField)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	in := &AllCase{}
+	if err := in.Default(); err != nil {
+		t.Fatal(err)
+	}
+	if in.Spec.DefaultedArrayField != nil {
+		t.Errorf("the fields of the unset spec were defaulted: %v", in.Spec.DefaultedArrayField)
+	}
+
+	in.Spec.StringField = "set"
+	in.Spec.DefaultedArrayField = []string{}
+	if err := in.Default(); err != nil {
+		t.Fatal(err)
+	}
+	if len(in.Spec.DefaultedArrayField) != 0 {
+		t.Errorf("the explicit empty value was replaced: %v", in.Spec.DefaultedArrayField)
+	}
+	if in.Spec.DefaultedBoolField {
+		t.Error("the explicit false value was replaced")
+	}
+
+	in.Spec.DefaultedArrayField = nil
+	if err := in.Default(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in.Spec.DefaultedArrayField, []string{"a", "b"}) {
+		t.Errorf("the unset field was not defaulted: %v", in.Spec.DefaultedArrayField)
+	}
+
+	var port *int32
+	if err := setDefault(&port, "\"a\""); err == nil || port != nil {
+		t.Errorf("the invalid default was applied: %v", err)
+	}
+}
+
+func TestValidateCEL(t *testing.T) {
+	in := &CelValidation{}
+	in.Name = "cel-test"
+	in.Spec.Mode = "Auto"
+	in.Spec.MinReplicas = 1
+	in.Spec.MaxReplicas = 2
+	if err := in.ValidateCEL(); err != nil {
+		t.Fatal(err)
+	}
+
+	in.Spec.OldSelfNote = "invalid"
+	err := in.ValidateCEL()
+	if err == nil || !strings.Contains(err.Error(), "oldSelfNote must not be invalid") {
+		t.Errorf("the rule referring to the oldSelfNote property was not evaluated: %v", err)
+	}
+}
+`
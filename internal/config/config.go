@@ -0,0 +1,50 @@
+// Package config reads the crd-gen configuration file.
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+// Config is the content of the configuration file.
+type Config struct {
+	// TypeOverrides replace the generated types of properties by schema path or struct name.
+	TypeOverrides []openapi.TypeOverride `json:"typeOverrides,omitempty"`
+	// KnownTypes are structs of the Kubernetes API packages used instead of generating structs for matching schemas.
+	KnownTypes []openapi.KnownTypeRef `json:"knownTypes,omitempty"`
+	// Initialisms are additional initialisms written in upper case in go names, e.g. OIDC.
+	Initialisms []string `json:"initialisms,omitempty"`
+	// FieldNames maps schema paths (spec.apiURL) or property names (apiURL) to explicit go field names.
+	FieldNames map[string]string `json:"fieldNames,omitempty"`
+}
+
+// Load reads and validates a yaml or json configuration file.
+func Load(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file %q: %w", file, err)
+	}
+	for i, o := range cfg.TypeOverrides {
+		if err := o.Validate(); err != nil {
+			return nil, fmt.Errorf("config file %q: typeOverrides[%d]: %w", file, i, err)
+		}
+	}
+	for i, k := range cfg.KnownTypes {
+		if _, err := k.Resolve(); err != nil {
+			return nil, fmt.Errorf("config file %q: knownTypes[%d]: %w", file, i, err)
+		}
+	}
+	if err := openapi.ValidateFieldNames(cfg.FieldNames); err != nil {
+		return nil, fmt.Errorf("config file %q: fieldNames: %w", file, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "crd-gen.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+typeOverrides:
+  - path: spec.template.spec
+    type: corev1.PodSpec
+    import: k8s.io/api/core/v1
+  - struct: Timeout
+    kind: Widget
+    type: metav1.Duration
+    import: k8s.io/apimachinery/pkg/apis/meta/v1
+knownTypes:
+  - type: corev1.Volume
+    import: k8s.io/api/core/v1
+initialisms:
+  - oidc
+fieldNames:
+  spec.apiURL: Endpoint
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []openapi.TypeOverride{
+		{Path: "spec.template.spec", Type: "corev1.PodSpec", Import: "k8s.io/api/core/v1"},
+		{Struct: "Timeout", Kind: "Widget", Type: "metav1.Duration", Import: "k8s.io/apimachinery/pkg/apis/meta/v1"},
+	}, cfg.TypeOverrides)
+	assert.Equal(t, []openapi.KnownTypeRef{{Type: "corev1.Volume", Import: "k8s.io/api/core/v1"}}, cfg.KnownTypes)
+	assert.Equal(t, []string{"oidc"}, cfg.Initialisms)
+	assert.Equal(t, map[string]string{"spec.apiURL": "Endpoint"}, cfg.FieldNames)
+}
+
+func TestLoad_errors(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = Load(writeConfig(t, "typeOverride: []"))
+	require.ErrorContains(t, err, `unknown field "typeOverride"`)
+
+	_, err = Load(writeConfig(t, `
+typeOverrides:
+  - path: spec.config
+    type: json.RawMessage
+`))
+	require.ErrorIs(t, err, openapi.ErrInvalidTypeOverride)
+	assert.ErrorContains(t, err, "typeOverrides[0]")
+
+	_, err = Load(writeConfig(t, `
+knownTypes:
+  - type: corev1.Widget
+    import: k8s.io/api/core/v1
+`))
+	require.ErrorIs(t, err, openapi.ErrUnknownKnownType)
+	assert.ErrorContains(t, err, "knownTypes[0]")
+
+	_, err = Load(writeConfig(t, `
+fieldNames:
+  apiURL: endpoint
+`))
+	require.ErrorIs(t, err, openapi.ErrInvalidFieldName)
+}
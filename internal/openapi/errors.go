@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoCRDFound is returned if the inputs contain no CRD matching the filters.
+	ErrNoCRDFound = errors.New("no CRD found")
+	// ErrNoGlobMatch is returned if no file matches a glob pattern.
+	ErrNoGlobMatch = errors.New("no files match the glob pattern")
+	// ErrVersionNotFound is returned if none of the desired versions is defined in a CRD.
+	ErrVersionNotFound = errors.New("could not find desired versions in CRD")
+	// ErrNoSchema is returned if a CRD version has no openAPIV3Schema.
+	ErrNoSchema = errors.New("no openAPIV3Schema defined")
+	// ErrUnsupportedType is returned if a property has a type that can not be mapped to a go type.
+	ErrUnsupportedType = errors.New("unsupported schema type")
+	// ErrUnsupportedItems is returned if an array defines a list of item schemas instead of a single schema.
+	ErrUnsupportedItems = errors.New("array items with multiple schemas are not supported")
+	// ErrInvalidDefault is returned if the default value of a property does not match the schema type.
+	ErrInvalidDefault = errors.New("invalid default")
+	// ErrInvalidEnum is returned if the values of an enum can not be generated as constants of the enum type.
+	ErrInvalidEnum = errors.New("invalid enum")
+	// ErrNoClientConfig is returned if a k8s: input is read without a client config.
+	ErrNoClientConfig = errors.New("no kubernetes client config defined")
+	// ErrHTTPStatus is returned if downloading an input does not respond with status OK.
+	ErrHTTPStatus = errors.New("unexpected http status downloading file")
+	// ErrDuplicateFieldName is returned if several properties of a schema have the same go field name.
+	ErrDuplicateFieldName = errors.New("duplicate field name")
+	// ErrInvalidFieldName is returned if an explicit field name is not an exported go identifier.
+	ErrInvalidFieldName = errors.New("invalid field name")
+	// ErrInvalidNamingStrategy is returned if the naming strategy is unknown.
+	ErrInvalidNamingStrategy = errors.New("invalid naming strategy")
+	// ErrStructNameCollision is returned if several structs or enum types of a package get the same name
+	// with the path or kind naming strategy.
+	ErrStructNameCollision = errors.New("struct name collision")
+	// ErrInvalidTypeOverride is returned if a type override is incomplete.
+	ErrInvalidTypeOverride = errors.New("invalid type override")
+	// ErrUnknownKnownType is returned if a known type reference is no struct of the Kubernetes API packages.
+	ErrUnknownKnownType = errors.New("unknown known type")
+)
+
+// ParseError is an error reading an input or parsing a CRD.
+// The input, CRD, version and schema path are set as far as they are known.
+type ParseError struct {
+	// Input is the CRD input as defined by the user.
+	Input string
+	// CRD is the name of the CRD.
+	CRD string
+	// Version is the CRD version.
+	Version string
+	// Path is the path of the property in the openAPIV3Schema.
+	Path string
+	// Err is the cause of the error.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	var location []string
+	if e.Input != "" {
+		location = append(location, fmt.Sprintf("input %q", e.Input))
+	}
+	if e.CRD != "" {
+		location = append(location, fmt.Sprintf("crd %q", e.CRD))
+	}
+	if e.Version != "" {
+		location = append(location, fmt.Sprintf("version %q", e.Version))
+	}
+	if e.Path != "" {
+		location = append(location, fmt.Sprintf("path %q", e.Path))
+	}
+	cause := "parse error"
+	if e.Err != nil {
+		cause = e.Err.Error()
+	}
+	if len(location) == 0 {
+		return cause
+	}
+	return strings.Join(location, ", ") + ": " + cause
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,176 @@
+package openapi
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	helmChartFile   = "Chart.yaml"
+	helmCRDsDir     = "crds"
+	helmChartsDir   = "charts"
+	helmReleaseName = "crd-gen"
+)
+
+// isHelmChart checks if the input is a packaged Helm chart or a chart directory.
+func isHelmChart(crd string) bool {
+	if strings.HasSuffix(crd, ".tgz") || strings.HasSuffix(crd, ".tar.gz") {
+		return true
+	}
+	if strings.HasPrefix(crd, "http://") || strings.HasPrefix(crd, "https://") || strings.HasPrefix(crd, "k8s:") {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(crd, helmChartFile))
+	return err == nil && !info.IsDir()
+}
+
+// readHelmChart reads the crds/ folders of a Helm chart and its sub charts.
+// If enabled, the templates of the chart are rendered with helm and added to the documents.
+func readHelmChart(ctx context.Context, chart string, opts Options) ([]byte, error) {
+	var files map[string][]byte
+	if info, err := os.Stat(chart); err == nil && info.IsDir() {
+		files, err = readChartDirCRDs(chart)
+		if err != nil {
+			return nil, fmt.Errorf("error reading helm chart: %w", err)
+		}
+	} else {
+		data, err := readCRD(ctx, chart, opts.ClientConfig)
+		if err != nil {
+			return nil, err
+		}
+		files, err = readChartArchiveCRDs(data)
+		if err != nil {
+			return nil, fmt.Errorf("error reading helm chart archive: %w", err)
+		}
+	}
+
+	var docs [][]byte
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		slog.DebugContext(ctx, "Reading CRDs of helm chart", "chart", chart, "file", name)
+		docs = append(docs, files[name])
+	}
+
+	if opts.HelmTemplates || len(opts.HelmValues) > 0 {
+		rendered, err := renderHelmTemplates(ctx, chart, opts.HelmValues)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, rendered)
+	}
+
+	if len(docs) == 0 {
+		slog.WarnContext(ctx, "No CRD files found in helm chart", "chart", chart)
+	}
+	return bytes.Join(docs, []byte("\n---\n")), nil
+}
+
+// readChartDirCRDs reads the files in the crds/ folders of a chart directory.
+func readChartDirCRDs(chart string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(chart, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(chart, p)
+		if err != nil {
+			return err
+		}
+		if !isChartCRDFile(filepath.ToSlash(rel)) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	return files, err
+}
+
+// readChartArchiveCRDs reads the files in the crds/ folders of a packaged chart.
+func readChartArchiveCRDs(data []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// the files of a packaged chart are located in a folder named after the chart
+		_, rel, found := strings.Cut(path.Clean(header.Name), "/")
+		if !found || !isChartCRDFile(rel) {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[rel] = content
+	}
+}
+
+// isChartCRDFile checks if a path relative to the chart root is a yaml or json file in the crds/ folder
+// of the chart or one of its sub charts.
+func isChartCRDFile(rel string) bool {
+	if !slices.Contains(crdFileExtensions, strings.ToLower(path.Ext(rel))) {
+		return false
+	}
+	segments := strings.Split(rel, "/")
+	for i, segment := range segments[:len(segments)-1] {
+		if segment != helmCRDsDir {
+			continue
+		}
+		if i == 0 || (i >= 2 && segments[i-2] == helmChartsDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderHelmTemplates renders the templates of a chart with the helm binary.
+func renderHelmTemplates(ctx context.Context, chart string, values []string) ([]byte, error) {
+	args := []string{"template", helmReleaseName, chart}
+	for _, v := range values {
+		args = append(args, "--values", v)
+	}
+
+	var execOut bytes.Buffer
+	var execErr bytes.Buffer
+	helmCmd := exec.CommandContext(ctx, "helm", args...)
+	helmCmd.Stdout = &execOut
+	helmCmd.Stderr = &execErr
+
+	slog.InfoContext(ctx, "Rendering helm chart", "chart", chart, "values", values)
+	if err := helmCmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to render helm templates: %w\nstderr: %s", err, execErr.String())
+	}
+	return execOut.Bytes(), nil
+}
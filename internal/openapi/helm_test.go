@@ -0,0 +1,18 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isChartCRDFile(t *testing.T) {
+	assert.True(t, isChartCRDFile("crds/widgets.yaml"))
+	assert.True(t, isChartCRDFile("crds/nested/widgets.yml"))
+	assert.True(t, isChartCRDFile("charts/sub/crds/gadgets.yaml"))
+	assert.True(t, isChartCRDFile("charts/sub/charts/subsub/crds/gadgets.json"))
+	assert.False(t, isChartCRDFile("crds/README.md"))
+	assert.False(t, isChartCRDFile("templates/crds/gizmos.yaml"))
+	assert.False(t, isChartCRDFile("values.yaml"))
+	assert.False(t, isChartCRDFile("crds.yaml"))
+}
@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// crdFileExtensions are the extensions of the files read from a directory.
+var crdFileExtensions = []string{".yaml", ".yml", ".json"}
+
+// expandInputs resolves directories and glob patterns of the local inputs into the files to read.
+// URLs and k8s: references are returned as they are.
+func expandInputs(crds []string) (inputs []string, errs []error) {
+	for _, crd := range crds {
+		if strings.HasPrefix(crd, "http://") || strings.HasPrefix(crd, "https://") || strings.HasPrefix(crd, "k8s:") {
+			inputs = append(inputs, crd)
+			continue
+		}
+
+		paths := []string{crd}
+		if hasGlobMeta(crd) {
+			matches, err := filepath.Glob(crd)
+			if err != nil {
+				errs = append(errs, &ParseError{Input: crd, Err: err})
+				continue
+			}
+			if len(matches) == 0 {
+				errs = append(errs, &ParseError{Input: crd, Err: ErrNoGlobMatch})
+				continue
+			}
+			paths = matches
+		}
+
+		for _, path := range paths {
+			files, err := crdFiles(path)
+			if err != nil {
+				errs = append(errs, &ParseError{Input: path, Err: err})
+				continue
+			}
+			inputs = append(inputs, files...)
+		}
+	}
+	return inputs, errs
+}
+
+// crdFiles returns the yaml and json files of a directory, or the path itself if it is not a directory.
+func crdFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		// let the reader report missing files
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{path}, nil
+		}
+		return nil, err
+	}
+	if !info.IsDir() || isHelmChart(path) {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && slices.Contains(crdFileExtensions, strings.ToLower(filepath.Ext(entry.Name()))) {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// splitDocuments splits a multi-document yaml stream into its non-empty documents.
+func splitDocuments(data []byte) ([][]byte, error) {
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	var docs [][]byte
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isEmptyDocument(doc) {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// isEmptyDocument checks if a document contains only whitespace and comments.
+func isEmptyDocument(doc []byte) bool {
+	for line := range strings.SplitSeq(string(doc), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") && line != "---" {
+			return false
+		}
+	}
+	return true
+}
+
+// selects checks if the CRD matches the kind and group filters.
+func (o Options) selects(crd *apiv1.CustomResourceDefinition) bool {
+	if len(o.Kinds) > 0 && !slices.ContainsFunc(o.Kinds, func(kind string) bool {
+		return strings.EqualFold(kind, crd.Spec.Names.Kind)
+	}) {
+		return false
+	}
+	return len(o.Groups) == 0 || slices.Contains(o.Groups, crd.Spec.Group)
+}
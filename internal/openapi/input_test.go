@@ -0,0 +1,38 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func Test_splitDocuments(t *testing.T) {
+	docs, err := splitDocuments([]byte(`---
+# leading comment only
+---
+kind: A
+---
+
+---
+kind: B
+`))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Contains(t, string(docs[0]), "kind: A")
+	assert.Contains(t, string(docs[1]), "kind: B")
+}
+
+func Test_Options_selects(t *testing.T) {
+	crd := &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+		Group: "testing.crd-gen",
+		Names: apiv1.CustomResourceDefinitionNames{Kind: "Widget"},
+	}}
+
+	assert.True(t, Options{}.selects(crd))
+	assert.True(t, Options{Kinds: []string{"widget"}}.selects(crd))
+	assert.True(t, Options{Kinds: []string{"Gadget", "Widget"}, Groups: []string{"testing.crd-gen"}}.selects(crd))
+	assert.False(t, Options{Kinds: []string{"Gadget"}}.selects(crd))
+	assert.False(t, Options{Groups: []string{"other.crd-gen"}}.selects(crd))
+}
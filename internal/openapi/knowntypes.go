@@ -0,0 +1,338 @@
+package openapi
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	corev1 "k8s.io/api/core/v1"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// KnownType is an existing go type that is used instead of generating a struct,
+// if a schema matches the json structure of the type.
+type KnownType struct {
+	// Type is the go struct type.
+	Type reflect.Type
+	// Alias is the package alias used in the generated code.
+	Alias string
+}
+
+// NewKnownType creates a known type of the go type T.
+func NewKnownType[T any](alias string) KnownType {
+	return KnownType{Type: reflect.TypeFor[T](), Alias: alias}
+}
+
+// DefaultKnownTypes returns the registry of well known Kubernetes types.
+func DefaultKnownTypes() []KnownType {
+	return []KnownType{
+		NewKnownType[corev1.Affinity]("corev1"),
+		NewKnownType[corev1.ConfigMapKeySelector]("corev1"),
+		NewKnownType[corev1.Container]("corev1"),
+		NewKnownType[corev1.ContainerPort]("corev1"),
+		NewKnownType[corev1.EnvFromSource]("corev1"),
+		NewKnownType[corev1.EnvVar]("corev1"),
+		NewKnownType[corev1.LocalObjectReference]("corev1"),
+		NewKnownType[corev1.NodeAffinity]("corev1"),
+		NewKnownType[corev1.ObjectReference]("corev1"),
+		NewKnownType[corev1.PodAffinity]("corev1"),
+		NewKnownType[corev1.PodAntiAffinity]("corev1"),
+		NewKnownType[corev1.PodSecurityContext]("corev1"),
+		NewKnownType[corev1.PodSpec]("corev1"),
+		NewKnownType[corev1.PodTemplateSpec]("corev1"),
+		NewKnownType[corev1.Probe]("corev1"),
+		NewKnownType[corev1.ResourceRequirements]("corev1"),
+		NewKnownType[corev1.SecretKeySelector]("corev1"),
+		NewKnownType[corev1.SecurityContext]("corev1"),
+		NewKnownType[corev1.Toleration]("corev1"),
+		NewKnownType[corev1.TopologySpreadConstraint]("corev1"),
+		NewKnownType[corev1.TypedLocalObjectReference]("corev1"),
+		NewKnownType[corev1.Volume]("corev1"),
+		NewKnownType[corev1.VolumeMount]("corev1"),
+		NewKnownType[metav1.LabelSelector]("metav1"),
+		NewKnownType[metav1.OwnerReference]("metav1"),
+	}
+}
+
+// KnownTypeRef references a struct of the Kubernetes API packages by its qualified go type and import path,
+// e.g. corev1.Volume imported from k8s.io/api/core/v1.
+type KnownTypeRef struct {
+	// Type is the go type qualified with the package alias used in the generated code.
+	Type string `json:"type"`
+	// Import is the import path of the package of the type.
+	Import string `json:"import"`
+}
+
+// Resolve returns the known type of the reference.
+// Only structs of the Kubernetes API packages can be resolved, other types must be defined with NewKnownType.
+func (k KnownTypeRef) Resolve() (KnownType, error) {
+	alias, name, ok := strings.Cut(k.Type, ".")
+	if !ok || !token.IsIdentifier(alias) || !token.IsIdentifier(name) {
+		return KnownType{}, fmt.Errorf("%w: type %q must be qualified with a package alias", ErrUnknownKnownType, k.Type)
+	}
+	t, ok := kubernetesAPITypes()[k.Import+"."+name]
+	if !ok {
+		return KnownType{}, fmt.Errorf("%w: %q is no struct of the Kubernetes API packages", ErrUnknownKnownType,
+			k.Import+"."+name)
+	}
+	return KnownType{Type: t, Alias: alias}, nil
+}
+
+// kubernetesAPITypes returns the structs of the Kubernetes API packages by import path and name,
+// collected from the fields of the kinds of the client-go scheme.
+var kubernetesAPITypes = sync.OnceValue(func() map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t.Name() == "" || !strings.HasPrefix(t.PkgPath(), "k8s.io/") {
+			return
+		}
+		key := t.PkgPath() + "." + t.Name()
+		if _, ok := types[key]; ok {
+			return
+		}
+		types[key] = t
+		for field := range t.Fields() {
+			if field.IsExported() {
+				collect(field.Type)
+			}
+		}
+	}
+	for _, t := range scheme.Scheme.AllKnownTypes() {
+		collect(t)
+	}
+	return types
+})
+
+// GoType returns the qualified go type name.
+func (k KnownType) GoType() string {
+	return k.Alias + "." + k.Type.Name()
+}
+
+// Import returns the import spec of the package of the type.
+func (k KnownType) Import() string {
+	return fmt.Sprintf("%s %q", k.Alias, k.Type.PkgPath())
+}
+
+var (
+	timeTypes = []reflect.Type{
+		reflect.TypeFor[metav1.Time](),
+		reflect.TypeFor[metav1.MicroTime](),
+		reflect.TypeFor[metav1.Duration](),
+	}
+	intOrStringTypes = []reflect.Type{
+		reflect.TypeFor[intstr.IntOrString](),
+		reflect.TypeFor[resource.Quantity](),
+	}
+	anyTypes = []reflect.Type{
+		reflect.TypeFor[runtime.RawExtension](),
+		reflect.TypeFor[apiv1.JSON](),
+	}
+)
+
+// knownTypeMatch is a known type matching a schema.
+type knownTypeMatch struct {
+	knownType KnownType
+	// missing is the number of optional fields of the type that are not defined in the schema.
+	missing int
+	// nameScore is the number of words of the type name contained in the property name.
+	nameScore int
+}
+
+// findKnownType finds the known type matching the schema of an object property.
+// A type matches if the schema properties are json fields of the type with a compatible schema and all fields
+// that are not defined in the schema are optional. The schema must define the majority of the fields.
+// If several types match, the one with the least missing fields is selected, then the one sharing the most words
+// with the property name. If the best match is still ambiguous, no type is returned.
+func findKnownType(knownTypes []KnownType, prop *apiv1.JSONSchemaProps, propName string) (KnownType, bool) {
+	if len(prop.Properties) == 0 {
+		return KnownType{}, false
+	}
+
+	var matches []knownTypeMatch
+	for _, kt := range knownTypes {
+		missing, ok := matchStruct(prop, kt.Type)
+		if !ok {
+			continue
+		}
+		matches = append(matches, knownTypeMatch{
+			knownType: kt,
+			missing:   missing,
+			nameScore: nameScore(propName, kt.Type.Name()),
+		})
+	}
+	if len(matches) == 0 {
+		return KnownType{}, false
+	}
+
+	slices.SortStableFunc(matches, func(a, b knownTypeMatch) int {
+		if a.missing != b.missing {
+			return a.missing - b.missing
+		}
+		return b.nameScore - a.nameScore
+	})
+	if len(matches) > 1 && matches[0].missing == matches[1].missing && matches[0].nameScore == matches[1].nameScore {
+		return KnownType{}, false
+	}
+	return matches[0].knownType, true
+}
+
+// matchStruct checks if the object schema matches the json fields of a struct type.
+// It returns the number of optional fields of the struct not defined in the schema.
+func matchStruct(prop *apiv1.JSONSchemaProps, t reflect.Type) (missing int, ok bool) {
+	if prop.Type != "object" || t.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	fields := jsonFields(t)
+	for name, p := range prop.Properties {
+		field, found := fields[name]
+		if !found || !matchSchema(&p, field.Type) {
+			return 0, false
+		}
+	}
+	for name, field := range fields {
+		if _, found := prop.Properties[name]; found {
+			continue
+		}
+		if !isOptionalField(field) {
+			return 0, false
+		}
+		missing++
+	}
+	if missing >= len(prop.Properties) {
+		// the schema must define the majority of the fields
+		return 0, false
+	}
+	return missing, true
+}
+
+// matchSchema checks if a schema is compatible with the json representation of a go type.
+func matchSchema(prop *apiv1.JSONSchemaProps, t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case slices.Contains(timeTypes, t):
+		return prop.Type == "string"
+	case slices.Contains(intOrStringTypes, t):
+		return prop.XIntOrString || prop.Type == "string" || len(prop.AnyOf) > 0
+	case slices.Contains(anyTypes, t):
+		return true
+	default:
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return prop.Type == "string"
+	case reflect.Bool:
+		return prop.Type == "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return prop.Type == "integer"
+	case reflect.Float32, reflect.Float64:
+		return prop.Type == "number"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return prop.Type == "string"
+		}
+		if prop.Type != "array" {
+			return false
+		}
+		return prop.Items == nil || prop.Items.Schema == nil || matchSchema(prop.Items.Schema, t.Elem())
+	case reflect.Map:
+		if prop.Type != "object" || len(prop.Properties) > 0 {
+			return false
+		}
+		return prop.AdditionalProperties == nil || prop.AdditionalProperties.Schema == nil ||
+			matchSchema(prop.AdditionalProperties.Schema, t.Elem())
+	case reflect.Struct:
+		if prop.Type != "object" {
+			return false
+		}
+		if len(prop.Properties) == 0 {
+			// objects without properties (e.g. metadata) accept any struct
+			return true
+		}
+		_, ok := matchStruct(prop, t)
+		return ok
+	case reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// jsonFields returns the json fields of a struct type, including the fields of inlined structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for field := range t.Fields() {
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && (name == "" || opts == "inline") && field.Type.Kind() == reflect.Struct {
+			for n, f := range jsonFields(field.Type) {
+				fields[n] = f
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func isOptionalField(field reflect.StructField) bool {
+	_, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+	for opt := range strings.SplitSeq(opts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			return true
+		}
+	}
+	return field.Type.Kind() == reflect.Pointer
+}
+
+// nameScore counts the words of the type name that are contained in the property name.
+func nameScore(propName, typeName string) int {
+	propName = strings.ToLower(propName)
+	score := 0
+	for _, word := range camelCaseWords(typeName) {
+		if strings.Contains(propName, strings.ToLower(word)) {
+			score++
+		}
+	}
+	return score
+}
+
+// camelCaseWords splits a CamelCase name into its words.
+func camelCaseWords(name string) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(name); i++ {
+		if unicode.IsUpper(rune(name[i])) && !unicode.IsUpper(rune(name[i-1])) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	return append(words, name[start:])
+}
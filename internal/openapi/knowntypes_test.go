@@ -0,0 +1,118 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func stringProp() apiv1.JSONSchemaProps {
+	return apiv1.JSONSchemaProps{Type: "string"}
+}
+
+func objectProp(props map[string]apiv1.JSONSchemaProps) *apiv1.JSONSchemaProps {
+	return &apiv1.JSONSchemaProps{Type: "object", Properties: props}
+}
+
+func Test_findKnownType(t *testing.T) {
+	knownTypes := DefaultKnownTypes()
+
+	toleration := objectProp(map[string]apiv1.JSONSchemaProps{
+		"effect":            stringProp(),
+		"key":               stringProp(),
+		"operator":          stringProp(),
+		"tolerationSeconds": {Type: "integer", Format: "int64"},
+		"value":             stringProp(),
+	})
+	kt, ok := findKnownType(knownTypes, toleration, "tolerations")
+	assert.True(t, ok)
+	assert.Equal(t, "corev1.Toleration", kt.GoType())
+	assert.Equal(t, `corev1 "k8s.io/api/core/v1"`, kt.Import())
+
+	keySelector := objectProp(map[string]apiv1.JSONSchemaProps{
+		"key":  stringProp(),
+		"name": stringProp(),
+	})
+	kt, ok = findKnownType(knownTypes, keySelector, "passwordSecretRef")
+	assert.True(t, ok)
+	assert.Equal(t, "corev1.SecretKeySelector", kt.GoType())
+	kt, ok = findKnownType(knownTypes, keySelector, "configMapRef")
+	assert.True(t, ok)
+	assert.Equal(t, "corev1.ConfigMapKeySelector", kt.GoType())
+	_, ok = findKnownType(knownTypes, keySelector, "keyRef")
+	assert.False(t, ok, "secret and config map key selectors are ambiguous")
+
+	labelSelector := objectProp(map[string]apiv1.JSONSchemaProps{
+		"matchExpressions": {Type: "array", Items: &apiv1.JSONSchemaPropsOrArray{Schema: objectProp(
+			map[string]apiv1.JSONSchemaProps{
+				"key":      stringProp(),
+				"operator": stringProp(),
+				"values":   {Type: "array", Items: &apiv1.JSONSchemaPropsOrArray{Schema: &apiv1.JSONSchemaProps{Type: "string"}}},
+			},
+		)}},
+		"matchLabels": {Type: "object", AdditionalProperties: &apiv1.JSONSchemaPropsOrBool{
+			Schema: &apiv1.JSONSchemaProps{Type: "string"},
+		}},
+	})
+	kt, ok = findKnownType(knownTypes, labelSelector, "selector")
+	assert.True(t, ok)
+	assert.Equal(t, "metav1.LabelSelector", kt.GoType())
+
+	// only the metadata of a PodTemplateSpec is not enough
+	template := objectProp(map[string]apiv1.JSONSchemaProps{
+		"metadata": *objectProp(map[string]apiv1.JSONSchemaProps{
+			"labels": {Type: "object", AdditionalProperties: &apiv1.JSONSchemaPropsOrBool{
+				Schema: &apiv1.JSONSchemaProps{Type: "string"},
+			}},
+		}),
+	})
+	_, ok = findKnownType(knownTypes, template, "podTemplate")
+	assert.False(t, ok)
+
+	// a type mismatch prevents the match
+	_, ok = findKnownType(knownTypes, objectProp(map[string]apiv1.JSONSchemaProps{
+		"name": {Type: "integer"},
+	}), "ref")
+	assert.False(t, ok)
+}
+
+type customRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func Test_findKnownType_custom(t *testing.T) {
+	ref := objectProp(map[string]apiv1.JSONSchemaProps{
+		"name":      stringProp(),
+		"namespace": stringProp(),
+	})
+	_, ok := findKnownType(nil, ref, "ref")
+	assert.False(t, ok)
+
+	kt, ok := findKnownType([]KnownType{NewKnownType[customRef]("custom")}, ref, "ref")
+	assert.True(t, ok)
+	assert.Equal(t, "custom.customRef", kt.GoType())
+	assert.Equal(t, `custom "github.com/bakito/crd-gen/internal/openapi"`, kt.Import())
+}
+
+func Test_KnownTypeRef_Resolve(t *testing.T) {
+	kt, err := KnownTypeRef{Type: "corev1.Volume", Import: "k8s.io/api/core/v1"}.Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, "corev1.Volume", kt.GoType())
+	assert.Equal(t, `corev1 "k8s.io/api/core/v1"`, kt.Import())
+
+	kt, err = KnownTypeRef{Type: "meta.LabelSelector", Import: "k8s.io/apimachinery/pkg/apis/meta/v1"}.Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, "meta.LabelSelector", kt.GoType())
+
+	for _, ref := range []KnownTypeRef{
+		{Type: "Volume", Import: "k8s.io/api/core/v1"},
+		{Type: "corev1.Volume", Import: "k8s.io/api/apps/v1"},
+		{Type: "json.RawMessage", Import: "encoding/json"},
+	} {
+		_, err := ref.Resolve()
+		require.ErrorIs(t, err, ErrUnknownKnownType, "%+v", ref)
+	}
+}
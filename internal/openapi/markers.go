@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const (
+	validationMarker      = "kubebuilder:validation:"
+	itemsValidationMarker = validationMarker + "items:"
+)
+
+// validationMarkers creates the kubebuilder markers for the constraints, defaults and list types of a property.
+// Constraints of array items are added with the items: prefix.
+func validationMarkers(prop *apiv1.JSONSchemaProps) []string {
+	markers := constraintMarkers(prop, validationMarker)
+
+	if prop.Items != nil && prop.Items.Schema != nil {
+		markers = append(markers, constraintMarkers(prop.Items.Schema, itemsValidationMarker)...)
+	}
+
+	if prop.Default != nil {
+		markers = append(markers, "kubebuilder:default="+string(prop.Default.Raw))
+	}
+	if prop.XListType != nil {
+		markers = append(markers, "listType="+*prop.XListType)
+	}
+	for _, key := range prop.XListMapKeys {
+		markers = append(markers, "listMapKey="+key)
+	}
+	if prop.XMapType != nil {
+		markers = append(markers, "mapType="+*prop.XMapType)
+	}
+	if prop.Nullable {
+		markers = append(markers, "nullable")
+	}
+	if prop.XEmbeddedResource {
+		markers = append(markers, validationMarker+"EmbeddedResource")
+	}
+	if prop.XPreserveUnknownFields != nil && *prop.XPreserveUnknownFields {
+		markers = append(markers, "kubebuilder:pruning:PreserveUnknownFields")
+	}
+	return markers
+}
+
+func constraintMarkers(prop *apiv1.JSONSchemaProps, prefix string) (markers []string) {
+	if prop.Minimum != nil {
+		markers = append(markers, prefix+"Minimum="+formatFloat(*prop.Minimum))
+	}
+	if prop.ExclusiveMinimum {
+		markers = append(markers, prefix+"ExclusiveMinimum=true")
+	}
+	if prop.Maximum != nil {
+		markers = append(markers, prefix+"Maximum="+formatFloat(*prop.Maximum))
+	}
+	if prop.ExclusiveMaximum {
+		markers = append(markers, prefix+"ExclusiveMaximum=true")
+	}
+	if prop.MultipleOf != nil {
+		markers = append(markers, prefix+"MultipleOf="+formatFloat(*prop.MultipleOf))
+	}
+	if prop.MinLength != nil {
+		markers = append(markers, fmt.Sprintf("%sMinLength=%d", prefix, *prop.MinLength))
+	}
+	if prop.MaxLength != nil {
+		markers = append(markers, fmt.Sprintf("%sMaxLength=%d", prefix, *prop.MaxLength))
+	}
+	if prop.Pattern != "" && !isQuantity(prop) {
+		// the quantity pattern is implied by resource.Quantity
+		markers = append(markers, prefix+"Pattern="+quoteMarkerValue(prop.Pattern))
+	}
+	if prop.MinItems != nil {
+		markers = append(markers, fmt.Sprintf("%sMinItems=%d", prefix, *prop.MinItems))
+	}
+	if prop.MaxItems != nil {
+		markers = append(markers, fmt.Sprintf("%sMaxItems=%d", prefix, *prop.MaxItems))
+	}
+	if prop.UniqueItems {
+		markers = append(markers, prefix+"UniqueItems=true")
+	}
+	if prop.MinProperties != nil {
+		markers = append(markers, fmt.Sprintf("%sMinProperties=%d", prefix, *prop.MinProperties))
+	}
+	if prop.MaxProperties != nil {
+		markers = append(markers, fmt.Sprintf("%sMaxProperties=%d", prefix, *prop.MaxProperties))
+	}
+	if prop.Format != "" && !isImpliedFormat(prop) {
+		markers = append(markers, prefix+"Format="+prop.Format)
+	}
+	for _, rule := range celRules(prop, "") {
+		markers = append(markers, prefix+"XValidation:"+rule.marker())
+	}
+	var values []string
+	for _, e := range prop.Enum {
+		// null values only express that the field is nullable
+		if decodeEnumValue(e.Raw) != nil {
+			values = append(values, string(e.Raw))
+		}
+	}
+	if len(values) > 0 {
+		markers = append(markers, prefix+"Enum="+strings.Join(values, ";"))
+	}
+	return markers
+}
+
+// isImpliedFormat checks if the format is already expressed by the go type created by mapType.
+func isImpliedFormat(prop *apiv1.JSONSchemaProps) bool {
+	switch prop.Type {
+	case "string":
+		return prop.Format == "date-time" || prop.Format == "byte" || prop.Format == "binary"
+	case "integer", "number":
+		switch prop.Format {
+		case "int32", "int64", "float", "double":
+			return true
+		default:
+		}
+	default:
+	}
+	return false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// quoteMarkerValue quotes a string marker value as raw string if possible.
+func quoteMarkerValue(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// celRules returns the x-kubernetes-validations rules of a property.
+func celRules(prop *apiv1.JSONSchemaProps, scope string) []ValidationRule {
+	rules := make([]ValidationRule, len(prop.XValidations))
+	for i, v := range prop.XValidations {
+		rules[i] = ValidationRule{
+			Rule:              v.Rule,
+			Message:           v.Message,
+			MessageExpression: v.MessageExpression,
+			FieldPath:         v.FieldPath,
+			Scope:             scope,
+		}
+		if v.Reason != nil {
+			rules[i].Reason = string(*v.Reason)
+		}
+	}
+	return rules
+}
+
+// propertyCELRules returns the x-kubernetes-validations rules of a property and its array items.
+// The rules of map values are not returned, as there is no marker to render them on the field.
+func propertyCELRules(prop *apiv1.JSONSchemaProps) []ValidationRule {
+	rules := celRules(prop, "")
+	if prop.Items != nil && prop.Items.Schema != nil {
+		rules = append(rules, celRules(prop.Items.Schema, "[*]")...)
+	}
+	return rules
+}
+
+func (v ValidationRule) marker() string {
+	args := []string{"rule=" + strconv.Quote(v.Rule)}
+	if v.Message != "" {
+		args = append(args, "message="+strconv.Quote(v.Message))
+	}
+	if v.MessageExpression != "" {
+		args = append(args, "messageExpression="+strconv.Quote(v.MessageExpression))
+	}
+	if v.Reason != "" {
+		args = append(args, "reason="+strconv.Quote(v.Reason))
+	}
+	if v.FieldPath != "" {
+		args = append(args, "fieldPath="+strconv.Quote(v.FieldPath))
+	}
+	return strings.Join(args, ",")
+}
+
+// resourceMarkers creates the kubebuilder markers of the resource names, scope, subresources and printer columns
+// of a CRD version, so controller-gen can generate an equivalent CRD from the root type.
+func resourceMarkers(
+	crd *apiv1.CustomResourceDefinition,
+	crdVersion *apiv1.CustomResourceDefinitionVersion,
+) (markers []string) {
+	names := crd.Spec.Names
+	resource := []string{"path=" + names.Plural}
+	if names.Singular != "" && names.Singular != strings.ToLower(names.Kind) {
+		resource = append(resource, "singular="+names.Singular)
+	}
+	if crd.Spec.Scope == apiv1.ClusterScoped {
+		resource = append(resource, "scope="+string(apiv1.ClusterScoped))
+	}
+	if len(names.ShortNames) > 0 {
+		resource = append(resource, "shortName="+strings.Join(names.ShortNames, ";"))
+	}
+	if len(names.Categories) > 0 {
+		resource = append(resource, "categories="+strings.Join(names.Categories, ";"))
+	}
+	markers = append(markers, "kubebuilder:resource:"+strings.Join(resource, ","))
+
+	if sub := crdVersion.Subresources; sub != nil {
+		if sub.Status != nil {
+			markers = append(markers, "kubebuilder:subresource:status")
+		}
+		if sub.Scale != nil {
+			scale := []string{
+				"specpath=" + sub.Scale.SpecReplicasPath,
+				"statuspath=" + sub.Scale.StatusReplicasPath,
+			}
+			if sub.Scale.LabelSelectorPath != nil {
+				scale = append(scale, "selectorpath="+*sub.Scale.LabelSelectorPath)
+			}
+			markers = append(markers, "kubebuilder:subresource:scale:"+strings.Join(scale, ","))
+		}
+	}
+
+	for _, col := range crdVersion.AdditionalPrinterColumns {
+		args := []string{
+			"name=" + strconv.Quote(col.Name),
+			"type=" + strconv.Quote(col.Type),
+			"JSONPath=" + strconv.Quote(col.JSONPath),
+		}
+		if col.Description != "" {
+			args = append(args, "description="+strconv.Quote(col.Description))
+		}
+		if col.Format != "" {
+			args = append(args, "format="+strconv.Quote(col.Format))
+		}
+		if col.Priority != 0 {
+			args = append(args, fmt.Sprintf("priority=%d", col.Priority))
+		}
+		markers = append(markers, "kubebuilder:printcolumn:"+strings.Join(args, ","))
+	}
+
+	for _, field := range crdVersion.SelectableFields {
+		markers = append(markers, "kubebuilder:selectablefield:JSONPath="+strconv.Quote(field.JSONPath))
+	}
+
+	// the storage version only needs to be marked if the CRD has several versions
+	if crdVersion.Storage && len(crd.Spec.Versions) > 1 {
+		markers = append(markers, "kubebuilder:storageversion")
+	}
+	if !crdVersion.Served {
+		markers = append(markers, "kubebuilder:unservedversion")
+	}
+	if crdVersion.Deprecated {
+		deprecated := "kubebuilder:deprecatedversion"
+		if crdVersion.DeprecationWarning != nil {
+			deprecated += ":warning=" + strconv.Quote(*crdVersion.DeprecationWarning)
+		}
+		markers = append(markers, deprecated)
+	}
+	return markers
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_resourceMarkers(t *testing.T) {
+	crd := &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+		Scope: apiv1.ClusterScoped,
+		Names: apiv1.CustomResourceDefinitionNames{
+			Kind:       "Widget",
+			Plural:     "widgets",
+			Singular:   "widget",
+			ShortNames: []string{"wd", "wdg"},
+		},
+		Versions: []apiv1.CustomResourceDefinitionVersion{
+			{Name: "v1alpha1", Deprecated: true, DeprecationWarning: ptr.To("use v1")},
+			{Name: "v1", Served: true, Storage: true},
+		},
+	}}
+
+	v1 := crd.Spec.Versions[1]
+	v1.Subresources = &apiv1.CustomResourceSubresources{
+		Status: &apiv1.CustomResourceSubresourceStatus{},
+		Scale: &apiv1.CustomResourceSubresourceScale{
+			SpecReplicasPath:   ".spec.replicas",
+			StatusReplicasPath: ".status.replicas",
+			LabelSelectorPath:  ptr.To(".status.selector"),
+		},
+	}
+	v1.AdditionalPrinterColumns = []apiv1.CustomResourceColumnDefinition{
+		{Name: "Ready", Type: "string", JSONPath: `.status.conditions[?(@.type=="Ready")].status`, Priority: 1},
+	}
+	assert.Equal(t, []string{
+		"kubebuilder:resource:path=widgets,scope=Cluster,shortName=wd;wdg",
+		"kubebuilder:subresource:status",
+		"kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector",
+		`kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",priority=1`,
+		"kubebuilder:storageversion",
+	}, resourceMarkers(crd, &v1))
+
+	assert.Equal(t, []string{
+		"kubebuilder:resource:path=widgets,scope=Cluster,shortName=wd;wdg",
+		"kubebuilder:unservedversion",
+		`kubebuilder:deprecatedversion:warning="use v1"`,
+	}, resourceMarkers(crd, &crd.Spec.Versions[0]))
+}
+
+func Test_validationMarkers_enum(t *testing.T) {
+	prop := &apiv1.JSONSchemaProps{
+		Type: "string",
+		Enum: []apiv1.JSON{{Raw: []byte(`"TCP"`)}, {Raw: []byte(`null`)}, {}, {Raw: []byte(`"UDP"`)}},
+	}
+	assert.Equal(t, []string{`kubebuilder:validation:Enum="TCP";"UDP"`}, validationMarkers(prop),
+		"null values are skipped")
+
+	assert.Empty(t, validationMarkers(&apiv1.JSONSchemaProps{Type: "string", Enum: []apiv1.JSON{{Raw: []byte(`null`)}}}))
+}
+
+func Test_validationMarkers_impliedFormat(t *testing.T) {
+	for _, format := range []string{"byte", "binary", "date-time"} {
+		assert.Empty(t, validationMarkers(&apiv1.JSONSchemaProps{Type: "string", Format: format}), format)
+	}
+	assert.Equal(t, []string{"kubebuilder:validation:Format=email"},
+		validationMarkers(&apiv1.JSONSchemaProps{Type: "string", Format: "email"}))
+}
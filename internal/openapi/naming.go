@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are the initialisms of the Go lint rules, written in upper case in go names.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CA": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GID": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true,
+	"SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// namer creates go names from schema property names.
+// The zero value uses the common initialisms only.
+type namer struct {
+	// initialisms are additional upper case initialisms.
+	initialisms map[string]bool
+	// fieldNames maps schema paths or property names to explicit field names.
+	fieldNames map[string]string
+}
+
+func newNamer(initialisms []string, fieldNames map[string]string) namer {
+	n := namer{initialisms: make(map[string]bool), fieldNames: fieldNames}
+	for _, i := range initialisms {
+		n.initialisms[strings.ToUpper(i)] = true
+	}
+	return n
+}
+
+// ValidateFieldNames checks if the explicit field names are exported go identifiers.
+func ValidateFieldNames(fieldNames map[string]string) error {
+	for key, name := range fieldNames {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("%w: %q of %q is not an exported go identifier", ErrInvalidFieldName, name, key)
+		}
+	}
+	return nil
+}
+
+// ValidateNamingStrategy checks if the naming strategy is known.
+func ValidateNamingStrategy(naming NamingStrategy) error {
+	switch naming {
+	case NamingShortest, NamingPath, NamingKind:
+		return nil
+	default:
+		return fmt.Errorf("%w %q", ErrInvalidNamingStrategy, naming)
+	}
+}
+
+// fieldName returns the go field name of a property. An explicit name for the schema path of the property
+// has precedence over a name for the property name.
+func (n namer) fieldName(schemaPath, propName string) string {
+	if name, ok := n.fieldNames[schemaPath]; ok {
+		return name
+	}
+	if name, ok := n.fieldNames[propName]; ok {
+		return name
+	}
+	return n.camelCase(propName)
+}
+
+// camelCase converts a string to CamelCase, writing initialisms in upper case.
+func (n namer) camelCase(s string) string {
+	var sb strings.Builder
+	for _, word := range splitWords(s) {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] || n.initialisms[upper] {
+			sb.WriteString(upper)
+		} else {
+			r := []rune(word)
+			sb.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+		}
+	}
+	return sb.String()
+}
+
+// splitWords splits a string into its words, separated by non-alphanumeric characters and camel case boundaries.
+// An upper case sequence is a word of its own, e.g. apiURLPath is split into api, URL and Path.
+func splitWords(s string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := !unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i])
+			endOfUpper := unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) &&
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || endOfUpper {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// ToCamelCase convert string to CamelCase, writing the common initialisms in upper case.
+func ToCamelCase(s string) string {
+	return namer{}.camelCase(s)
+}
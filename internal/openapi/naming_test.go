@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func Test_ToCamelCase(t *testing.T) {
+	for in, expected := range map[string]string{
+		"name":            "Name",
+		"apiURL":          "APIURL",
+		"apiVersion":      "APIVersion",
+		"clusterId":       "ClusterID",
+		"tls-ca":          "TLSCA",
+		"caBundle":        "CABundle",
+		"httpGet":         "HTTPGet",
+		"HTTPServer":      "HTTPServer",
+		"externalIPs":     "ExternalIPs",
+		"uuid_field":      "UUIDField",
+		"int32Field":      "Int32Field",
+		"v1beta1":         "V1beta1",
+		"oidcIssuerURL":   "OidcIssuerURL",
+		"already-Camel":   "AlreadyCamel",
+		"some.dotted.key": "SomeDottedKey",
+	} {
+		assert.Equal(t, expected, ToCamelCase(in), in)
+	}
+}
+
+func Test_namer(t *testing.T) {
+	n := newNamer([]string{"oidc"}, map[string]string{
+		"spec.apiURL": "Endpoint",
+		"apiURL":      "ServerURL",
+	})
+	assert.Equal(t, "OIDCIssuerURL", n.camelCase("oidcIssuerURL"))
+	assert.Equal(t, "Endpoint", n.fieldName("spec.apiURL", "apiURL"))
+	assert.Equal(t, "ServerURL", n.fieldName("status.apiURL", "apiURL"))
+	assert.Equal(t, "ClientID", n.fieldName("spec.clientId", "clientId"))
+}
+
+func Test_ValidateFieldNames(t *testing.T) {
+	require.NoError(t, ValidateFieldNames(map[string]string{"apiURL": "Endpoint"}))
+	require.ErrorIs(t, ValidateFieldNames(map[string]string{"apiURL": "endpoint"}), ErrInvalidFieldName)
+	require.ErrorIs(t, ValidateFieldNames(map[string]string{"apiURL": "End-point"}), ErrInvalidFieldName)
+}
+
+// parseSpec parses a Widget CRD with the properties as spec.
+func parseSpec(r *CustomResources, props map[string]apiv1.JSONSchemaProps) (*CustomResource, []*ParseError) {
+	crd := &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+		Names: apiv1.CustomResourceDefinitionNames{Kind: "Widget"},
+	}}
+	return r.parseCRD(crd, &apiv1.CustomResourceDefinitionVersion{
+		Name: "v1",
+		Schema: &apiv1.CustomResourceValidation{OpenAPIV3Schema: &apiv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiv1.JSONSchemaProps{
+				"spec": {Type: "object", Properties: props},
+			},
+		}},
+	})
+}
+
+func Test_generateStructs_duplicateFieldName(t *testing.T) {
+	r := &CustomResources{
+		structHashes: make(map[string]string),
+		structNames:  make(map[string]bool),
+	}
+	_, errs := parseSpec(r, map[string]apiv1.JSONSchemaProps{
+		"clusterID": {Type: "string"},
+		"clusterId": {Type: "string"},
+	})
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "spec.clusterId", errs[0].Path)
+	require.ErrorIs(t, errs[0], ErrDuplicateFieldName)
+}
+
+func Test_generateStructs_duplicateFieldNameResolved(t *testing.T) {
+	// fooID and fooId were generated as FooID and FooId before the initialisms were written in upper case
+	props := map[string]apiv1.JSONSchemaProps{
+		"fooID": {Type: "string"},
+		"fooId": {Type: "string"},
+	}
+	r := &CustomResources{
+		structHashes: make(map[string]string),
+		structNames:  make(map[string]bool),
+	}
+	_, errs := parseSpec(r, props)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "spec.fooId", errs[0].Path)
+	require.ErrorIs(t, errs[0], ErrDuplicateFieldName)
+
+	r = &CustomResources{
+		structHashes: make(map[string]string),
+		structNames:  make(map[string]bool),
+		names:        newNamer(nil, map[string]string{"spec.fooId": "FooId"}),
+	}
+	cr, errs := parseSpec(r, props)
+	require.Empty(t, errs)
+	var names []string
+	for _, f := range cr.Structs["WidgetSpec"].Fields {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"FooID", "FooId"}, names)
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// TypeOverride replaces the generated type of a property with an existing go type.
+// The schema of an overridden property is not generated.
+type TypeOverride struct {
+	// Path is the json path of the property in the schema, e.g. spec.template.spec.
+	Path string `json:"path,omitempty"`
+	// Struct is the name of a generated struct, all properties of the struct type use the override instead.
+	Struct string `json:"struct,omitempty"`
+	// Kind restricts the override to the CRDs of a kind. If empty, the override applies to all kinds.
+	Kind string `json:"kind,omitempty"`
+	// Type is the go type, qualified with the package name if imported, e.g. metav1.Duration.
+	Type string `json:"type"`
+	// Import is the import path of the package of the type, e.g. k8s.io/apimachinery/pkg/apis/meta/v1.
+	Import string `json:"import,omitempty"`
+}
+
+// qualifiedTypePattern matches the package name of a qualified go type expression like []*corev1.Container.
+var qualifiedTypePattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.[A-Za-z_]`)
+
+// Validate checks if the override is complete.
+func (o TypeOverride) Validate() error {
+	if (o.Path == "") == (o.Struct == "") {
+		return fmt.Errorf("%w: exactly one of path or struct must be defined", ErrInvalidTypeOverride)
+	}
+	if o.Type == "" {
+		return fmt.Errorf("%w: type must be defined", ErrInvalidTypeOverride)
+	}
+	qualified := qualifiedTypePattern.MatchString(o.Type)
+	if qualified && o.Import == "" {
+		return fmt.Errorf("%w: the import of type %q must be defined", ErrInvalidTypeOverride, o.Type)
+	}
+	if !qualified && o.Import != "" {
+		return fmt.Errorf("%w: type %q must be qualified with the package name of %q",
+			ErrInvalidTypeOverride, o.Type, o.Import)
+	}
+	return nil
+}
+
+// importSpec returns the import spec of the package of the type, using the package name of the type as alias
+// if it differs from the last element of the import path.
+func (o TypeOverride) importSpec() string {
+	m := qualifiedTypePattern.FindStringSubmatch(o.Type)
+	if o.Import == "" || m == nil {
+		return ""
+	}
+	if m[1] == path.Base(o.Import) {
+		return fmt.Sprintf("%q", o.Import)
+	}
+	return fmt.Sprintf("%s %q", m[1], o.Import)
+}
+
+// typeOverride finds the override of a property by its schema path or of a struct by its name.
+func (r *CustomResources) typeOverride(cr *CustomResource, propPath, structName string) (string, bool) {
+	for _, o := range r.typeOverrides {
+		if o.Kind != "" && o.Kind != cr.Kind {
+			continue
+		}
+		if (propPath != "" && o.Path == propPath) || (structName != "" && o.Struct == structName) {
+			if spec := o.importSpec(); spec != "" {
+				cr.Imports[spec] = true
+			}
+			return o.Type, true
+		}
+	}
+	return "", false
+}
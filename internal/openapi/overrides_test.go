@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func Test_TypeOverride_Validate(t *testing.T) {
+	require.NoError(t, TypeOverride{Path: "spec.config", Type: "[]byte"}.Validate())
+	require.NoError(t, TypeOverride{Struct: "Config", Type: "MyConfig"}.Validate())
+	require.NoError(t, TypeOverride{Path: "spec.config", Type: "json.RawMessage", Import: "encoding/json"}.Validate())
+
+	for _, o := range []TypeOverride{
+		{Type: "string"},
+		{Path: "spec.config", Struct: "Config", Type: "string"},
+		{Path: "spec.config"},
+		{Path: "spec.config", Type: "json.RawMessage"},
+		{Path: "spec.config", Type: "RawMessage", Import: "encoding/json"},
+	} {
+		require.ErrorIs(t, o.Validate(), ErrInvalidTypeOverride, "%+v", o)
+	}
+}
+
+func Test_TypeOverride_importSpec(t *testing.T) {
+	assert.Equal(t, `"encoding/json"`,
+		TypeOverride{Type: "json.RawMessage", Import: "encoding/json"}.importSpec())
+	assert.Equal(t, `corev1 "k8s.io/api/core/v1"`,
+		TypeOverride{Type: "[]*corev1.Container", Import: "k8s.io/api/core/v1"}.importSpec())
+	assert.Empty(t, TypeOverride{Type: "MyConfig"}.importSpec())
+}
+
+func Test_generateStructs_typeOverrides(t *testing.T) {
+	r := &CustomResources{
+		structHashes: make(map[string]string),
+		structNames:  make(map[string]bool),
+		typeOverrides: []TypeOverride{
+			{Path: "spec.template", Type: "corev1.PodTemplateSpec", Import: "k8s.io/api/core/v1"},
+			{Path: "spec.timeout", Type: "metav1.Duration", Import: "k8s.io/apimachinery/pkg/apis/meta/v1"},
+			{Struct: "Endpoints", Type: "MyEndpoint"},
+			{Struct: "Settings", Kind: "Other", Type: "MySettings"},
+		},
+	}
+	crd := &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+		Names: apiv1.CustomResourceDefinitionNames{Kind: "Widget"},
+	}}
+	object := func(props map[string]apiv1.JSONSchemaProps) apiv1.JSONSchemaProps {
+		return apiv1.JSONSchemaProps{Type: "object", Properties: props}
+	}
+	cr, errs := r.parseCRD(crd, &apiv1.CustomResourceDefinitionVersion{
+		Name: "v1",
+		Schema: &apiv1.CustomResourceValidation{OpenAPIV3Schema: &apiv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiv1.JSONSchemaProps{
+				"spec": object(map[string]apiv1.JSONSchemaProps{
+					"template": object(map[string]apiv1.JSONSchemaProps{
+						"spec": object(map[string]apiv1.JSONSchemaProps{"image": {Type: "string"}}),
+					}),
+					// the override bypasses the unsupported type
+					"timeout": {Type: "decimal"},
+					"endpoints": {Type: "array", Items: &apiv1.JSONSchemaPropsOrArray{
+						Schema: &apiv1.JSONSchemaProps{
+							Type:       "object",
+							Properties: map[string]apiv1.JSONSchemaProps{"url": {Type: "string"}},
+						},
+					}},
+					"settings": object(map[string]apiv1.JSONSchemaProps{"debug": {Type: "boolean"}}),
+				}),
+			},
+		}},
+	})
+	require.Empty(t, errs)
+
+	types := make(map[string]string)
+	for _, f := range cr.Structs["WidgetSpec"].Fields {
+		types[f.JSONTag] = f.Type
+	}
+	assert.Equal(t, map[string]string{
+		"template":  "corev1.PodTemplateSpec",
+		"timeout":   "metav1.Duration",
+		"endpoints": "[]MyEndpoint",
+		"settings":  "Settings",
+	}, types)
+	assert.ElementsMatch(t, []string{"WidgetSpec", "Settings"}, slices.Collect(maps.Keys(cr.Structs)),
+		"overridden subtrees are skipped")
+	assert.True(t, cr.Imports[`corev1 "k8s.io/api/core/v1"`])
+}
+
+func Test_generateStructs_typeOverrideSharedSchema(t *testing.T) {
+	for naming, structName := range map[NamingStrategy]string{NamingShortest: "Target", NamingKind: "WidgetTarget"} {
+		t.Run(structName, func(t *testing.T) {
+			r := &CustomResources{
+				naming:        naming,
+				structHashes:  make(map[string]string),
+				structNames:   make(map[string]bool),
+				typeOverrides: []TypeOverride{{Struct: structName, Type: "json.RawMessage", Import: "encoding/json"}},
+			}
+			crd := &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+				Names: apiv1.CustomResourceDefinitionNames{Kind: "Widget"},
+			}}
+			object := func(props map[string]apiv1.JSONSchemaProps) apiv1.JSONSchemaProps {
+				return apiv1.JSONSchemaProps{Type: "object", Properties: props}
+			}
+			target := object(map[string]apiv1.JSONSchemaProps{"name": {Type: "string"}})
+			cr, errs := r.parseCRD(crd, &apiv1.CustomResourceDefinitionVersion{
+				Name: "v1",
+				Schema: &apiv1.CustomResourceValidation{OpenAPIV3Schema: &apiv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiv1.JSONSchemaProps{
+						"spec": object(map[string]apiv1.JSONSchemaProps{
+							"a": object(map[string]apiv1.JSONSchemaProps{"target": target}),
+							"b": object(map[string]apiv1.JSONSchemaProps{"target": target, "size": {Type: "integer"}}),
+						}),
+					},
+				}},
+			})
+			require.Empty(t, errs)
+
+			var targets int
+			for name, st := range cr.Structs {
+				assert.NotEqual(t, structName, name, "the overridden struct is not generated")
+				for _, f := range st.Fields {
+					if f.JSONTag == "target" {
+						targets++
+						assert.Equal(t, "json.RawMessage", f.Type, "%s.%s", name, f.Name)
+					}
+				}
+			}
+			assert.Equal(t, 2, targets, "both properties use the override")
+		})
+	}
+}
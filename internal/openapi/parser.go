@@ -4,21 +4,25 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"maps"
+	"math"
 	"net/http"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
-	"unicode"
 
 	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -28,187 +32,342 @@ const (
 	enumEmptyValue  = "EmptyValue"
 )
 
-var k8sConfig clientcmd.ClientConfig
-
-func Parse(
-	ctx context.Context,
-	k8sCfg clientcmd.ClientConfig,
-	crds []string,
-	version string,
-	pointerVars bool,
-) (res *CustomResources, success bool) {
-	k8sConfig = k8sCfg
-	res = &CustomResources{
-		structHashes: make(map[string]string),
-		structNames:  make(map[string]bool),
-		Version:      version,
-	}
-	var crdKind string
-
-	for i, crd := range crds {
-		var ok bool
-		if crdKind, ok = prepareCRD(ctx, crd, res, crdKind, version, i == 0); !ok {
-			return nil, false
-		}
-	}
-
-	if pointerVars {
-		// convert fields to pointers - there is room for improvement here, but it works for now
-		for i, item := range res.Items {
-			for s, def := range item.Structs {
-				for f, field := range def.Fields {
-					if !field.NoPointer {
-						if strings.Contains(field.Type, "]") {
-							// handle slice and maps
-							res.Items[i].Structs[s].Fields[f].Type = strings.Replace(field.Type, "]", "]*", 1)
-						} else {
-							res.Items[i].Structs[s].Fields[f].Type = "*" + field.Type
-						}
+// Parse reads the CRD inputs and parses the selected CRD versions into a package per group version.
+// All failures are collected and returned as joined *ParseError.
+func Parse(ctx context.Context, crds []string, opts Options) ([]*CustomResources, error) {
+	inputs, errs := expandInputs(crds)
+
+	var defs []Definition
+	for _, crd := range inputs {
+		inputDefs, err := readCRDDefinitions(ctx, crd, opts)
+		if err != nil {
+			errs = append(errs, &ParseError{Input: crd, Err: err})
+			continue
+		}
+		defs = append(defs, inputDefs...)
+	}
+
+	res, parseErrs := parseDefinitions(defs, opts)
+	errs = append(errs, parseErrs...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("%w in %q (kinds: %q, groups: %q)", ErrNoCRDFound, crds, opts.Kinds, opts.Groups)
+	}
+	return res, nil
+}
+
+// ParseDefinitions parses the selected versions of already decoded CRDs into a package per group version.
+// All failures are collected and returned as joined *ParseError.
+func ParseDefinitions(defs []Definition, opts Options) ([]*CustomResources, error) {
+	res, errs := parseDefinitions(defs, opts)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("%w (kinds: %q, groups: %q)", ErrNoCRDFound, opts.Kinds, opts.Groups)
+	}
+	return res, nil
+}
+
+func parseDefinitions(defs []Definition, opts Options) ([]*CustomResources, []error) {
+	for _, o := range opts.TypeOverrides {
+		if err := o.Validate(); err != nil {
+			return nil, []error{err}
+		}
+	}
+	if err := ValidateFieldNames(opts.FieldNames); err != nil {
+		return nil, []error{err}
+	}
+	if err := ValidateNamingStrategy(opts.Naming); err != nil {
+		return nil, []error{err}
+	}
+
+	packages := make(map[schema.GroupVersion]*CustomResources)
+	kindNames := groupKindNames(defs, opts)
+	seen := make(map[schema.GroupKind]bool)
+	var errs []error
+	for _, def := range defs {
+		gk := schema.GroupKind{Group: def.CRD.Spec.Group, Kind: def.CRD.Spec.Names.Kind}
+		if seen[gk] || !opts.selects(def.CRD) {
+			continue
+		}
+		seen[gk] = true
+
+		for _, err := range prepareCRD(def.CRD, packages, opts, kindNames[gk.Group]) {
+			err.Input = def.Input
+			errs = append(errs, err)
+		}
+	}
+
+	res := make([]*CustomResources, 0, len(packages))
+	for _, gv := range slices.SortedFunc(maps.Keys(packages), func(a, b schema.GroupVersion) int {
+		if a.Group != b.Group {
+			return strings.Compare(a.Group, b.Group)
+		}
+		return version.CompareKubeAwareVersionStrings(b.Version, a.Version)
+	}) {
+		packages[gv].applyPointers(opts.Pointers)
+		res = append(res, packages[gv])
+	}
+	return res, errs
+}
+
+// applyPointers converts the struct fields to pointers according to the pointer mode.
+func (r *CustomResources) applyPointers(pointers PointerMode) {
+	for _, item := range r.Items {
+		for _, def := range item.Structs {
+			for f, field := range def.Fields {
+				if field.NoPointer {
+					continue
+				}
+				switch pointers {
+				case PointerAll:
+					// convert fields to pointers - there is room for improvement here, but it works for now
+					if strings.Contains(field.Type, "]") {
+						// handle slice and maps
+						def.Fields[f].Type = strings.Replace(field.Type, "]", "]*", 1)
+					} else {
+						def.Fields[f].Type = "*" + field.Type
 					}
+				case PointerOptional:
+					// only optional scalar and struct fields are converted, slices and maps can already be nil
+					if !field.Required && !strings.HasPrefix(field.Type, "[]") &&
+						!strings.HasPrefix(field.Type, "map[") && field.Type != "any" {
+						def.Fields[f].Type = "*" + field.Type
+					}
+				default:
 				}
 			}
 		}
 	}
+}
 
-	return res, true
+// groupKindNames returns the kind and list names of the selected CRDs by group. They are reserved in the packages
+// of the group, so the structs and enum types of properties do not get the name of a kind.
+func groupKindNames(defs []Definition, opts Options) map[string][]string {
+	names := make(map[string][]string)
+	for _, def := range defs {
+		if !opts.selects(def.CRD) {
+			continue
+		}
+		crdNames := def.CRD.Spec.Names
+		list := crdNames.ListKind
+		if list == "" {
+			list = crdNames.Kind + "List"
+		}
+		names[def.CRD.Spec.Group] = append(names[def.CRD.Spec.Group], crdNames.Kind, list)
+	}
+	return names
 }
 
-func prepareCRD(ctx context.Context, crd string, res *CustomResources, crdKind, version string, isFirst bool) (string, bool) {
-	data, ok := readCRD(ctx, crd)
-	if !ok {
-		return "", false
+// prepareCRD parses the selected versions of the CRD into the package of each group version.
+// The reserved names are not used for the structs and enum types of the package.
+func prepareCRD(
+	crd *apiv1.CustomResourceDefinition,
+	packages map[schema.GroupVersion]*CustomResources,
+	opts Options,
+	reserved []string,
+) []*ParseError {
+	selected, err := selectVersions(crd, opts.Versions)
+	if err != nil {
+		return []*ParseError{{CRD: crd.Name, Err: err}}
+	}
+
+	var errs []*ParseError
+	for _, v := range selected {
+		gv := schema.GroupVersion{Group: crd.Spec.Group, Version: v.Name}
+		res, ok := packages[gv]
+		if !ok {
+			res = &CustomResources{
+				structHashes:  make(map[string]string),
+				structNames:   make(map[string]bool),
+				knownTypes:    opts.KnownTypes,
+				typeOverrides: opts.TypeOverrides,
+				names:         newNamer(opts.Initialisms, opts.FieldNames),
+				naming:        opts.Naming,
+				Group:         crd.Spec.Group,
+				Version:       v.Name,
+			}
+			for _, name := range reserved {
+				res.structNames[name] = true
+			}
+			packages[gv] = res
+		}
+
+		cr, schemaErrs := res.parseCRD(crd, &v)
+		for _, err := range schemaErrs {
+			err.CRD = crd.Name
+			err.Version = v.Name
+			errs = append(errs, err)
+		}
+		if len(schemaErrs) > 0 {
+			continue
+		}
+		res.Names = append(res.Names, CRDNames{Kind: cr.Kind, List: cr.List})
+		res.Items = append(res.Items, cr)
 	}
+	return errs
+}
 
-	cr, err := res.parseCRD(data, res.Version)
+// readCRDDefinitions reads and decodes all CRDs of an input.
+func readCRDDefinitions(ctx context.Context, crd string, opts Options) ([]Definition, error) {
+	var data []byte
+	var err error
+	if isHelmChart(crd) {
+		data, err = readHelmChart(ctx, crd, opts)
+	} else {
+		data, err = readCRD(ctx, crd, opts.ClientConfig)
+	}
 	if err != nil {
-		slog.ErrorContext(ctx, "Error parsing crd", "error", err)
-		return "", false
+		return nil, err
 	}
-	res.Names = append(res.Names, CRDNames{Kind: cr.Kind, List: cr.List})
+	return DecodeDefinitions(ctx, crd, data)
+}
 
-	if !isFirst && res.Group != cr.group {
-		slog.ErrorContext(ctx,
-			"Not all CRD have the same group",
-			"group-a", res.Group, "kind-a", crdKind,
-			"group-b", cr.group, "kind-b", cr.Kind,
-		)
-		return "", false
+// DecodeDefinitions decodes all CRDs of a single or multi-document yaml or json input.
+// Documents that are not a CustomResourceDefinition are skipped.
+func DecodeDefinitions(ctx context.Context, input string, data []byte) ([]Definition, error) {
+	docs, err := splitDocuments(data)
+	if err != nil {
+		return nil, fmt.Errorf("error reading documents: %w", err)
 	}
 
-	if version != "" && version != cr.version {
-		slog.ErrorContext(ctx,
-			"Not all CRD have the same version",
-			"group-a", res.Group, "version-a", version, "kind-a", crdKind,
-			"group-b", cr.group, "version-b", cr.version, "kind-b", cr.Kind,
-		)
-		return "", false
+	var defs []Definition
+	for i, doc := range docs {
+		var meta metav1.TypeMeta
+		if err := yaml.Unmarshal(doc, &meta); err != nil {
+			return nil, fmt.Errorf("error parsing document %d: %w", i+1, err)
+		}
+		if meta.GroupVersionKind() != apiv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") {
+			slog.DebugContext(ctx, "Skipping non-CRD document",
+				"crd", input, "apiVersion", meta.APIVersion, "kind", meta.Kind)
+			continue
+		}
+
+		// Parse CRD YAML
+		var def apiv1.CustomResourceDefinition
+		if err := yaml.Unmarshal(doc, &def); err != nil {
+			return nil, fmt.Errorf("error parsing crd in document %d: %w", i+1, err)
+		}
+		// Apply the same defaulting the Kubernetes API server does so an unset
+		// listKind defaults to <Kind>List.
+		apiv1.SetObjectDefaults_CustomResourceDefinition(&def)
+		defs = append(defs, Definition{Input: input, CRD: &def})
 	}
-	res.Version = cr.version
-	res.Group = cr.group
-	res.Items = append(res.Items, cr)
-	return cr.Kind, true
+	return defs, nil
 }
 
-func readCRD(ctx context.Context, crd string) ([]byte, bool) {
+func readCRD(ctx context.Context, crd string, k8sConfig clientcmd.ClientConfig) ([]byte, error) {
 	// Read the first crd file
 	var data []byte
 	var err error
 	switch {
 	case strings.HasPrefix(crd, "http://") || strings.HasPrefix(crd, "https://"):
 		// Download the file to a temp location
-		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, crd, http.NoBody)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, crd, http.NoBody)
 		if err != nil {
-			slog.ErrorContext(ctx, "Error creating request", "error", err)
-			return nil, false
+			return nil, fmt.Errorf("error creating request: %w", err)
 		}
 
 		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
-			slog.ErrorContext(ctx, "Error downloading file", "error", err)
-			return nil, false
+			return nil, fmt.Errorf("error downloading file: %w", err)
 		}
 		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%w: %s", ErrHTTPStatus, resp.Status)
+		}
 
 		data, err = io.ReadAll(resp.Body)
 		if err != nil {
-			slog.ErrorContext(ctx, "Error reading downloaded file", "error", err)
-			return nil, false
+			return nil, fmt.Errorf("error reading downloaded file: %w", err)
 		}
 
 	case strings.HasPrefix(crd, "k8s:"):
 		// Fetch CRD via k8s client
+		if k8sConfig == nil {
+			return nil, ErrNoClientConfig
+		}
 		conf, err := k8sConfig.ClientConfig()
 		if err != nil {
-			slog.ErrorContext(ctx, "Error creating k8s client config", "error", err)
-			return nil, false
+			return nil, fmt.Errorf("error creating k8s client config: %w", err)
 		}
 
 		crdName := strings.TrimPrefix(crd, "k8s:")
 		client, err := clientset.NewForConfig(conf)
 		if err != nil {
-			slog.ErrorContext(ctx, "Error creating k8s client", "error", err)
-			return nil, false
+			return nil, fmt.Errorf("error creating k8s client: %w", err)
 		}
 
 		crdDef, err := client.ApiextensionsV1().
 			CustomResourceDefinitions().
-			Get(context.Background(), crdName, metav1.GetOptions{})
+			Get(ctx, crdName, metav1.GetOptions{})
 		if err != nil {
-			slog.ErrorContext(ctx, "Error getting CRD", "error", err)
-			return nil, false
+			return nil, fmt.Errorf("error getting CRD: %w", err)
 		}
 
+		// the typed client does not return the type meta
+		crdDef.SetGroupVersionKind(apiv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
 		data, err = json.Marshal(crdDef)
 		if err != nil {
-			slog.ErrorContext(ctx, "Error marshaling CRD", "error", err)
-			return nil, false
+			return nil, fmt.Errorf("error marshaling CRD: %w", err)
 		}
 
 	default:
 		// Read the local file
 		data, err = os.ReadFile(crd)
 		if err != nil {
-			slog.ErrorContext(ctx, "Error reading file", "error", err)
-			return nil, false
+			return nil, fmt.Errorf("error reading file: %w", err)
 		}
 	}
-	return data, true
+	return data, nil
 }
 
-func (r *CustomResources) parseCRD(crdData []byte, desiredVersion string) (*CustomResource, error) {
-	// Parse CRD YAML
-	var crd apiv1.CustomResourceDefinition
-	err := yaml.Unmarshal(crdData, &crd)
-	if err != nil {
-		return nil, err
-	}
-	// Apply the same defaulting the Kubernetes API server does so an unset
-	// listKind defaults to <Kind>List.
-	apiv1.SetObjectDefaults_CustomResourceDefinition(&crd)
-
-	// Extract schema
-	schema, version, err := extractSchemas(crd, desiredVersion)
-	if err != nil {
-		return nil, err
+func (r *CustomResources) parseCRD(
+	crd *apiv1.CustomResourceDefinition,
+	crdVersion *apiv1.CustomResourceDefinitionVersion,
+) (*CustomResource, []*ParseError) {
+	if crdVersion.Schema == nil || crdVersion.Schema.OpenAPIV3Schema == nil {
+		return nil, []*ParseError{{Err: ErrNoSchema}}
 	}
 
 	cr := &CustomResource{
-		Kind:    crd.Spec.Names.Kind,
-		Plural:  crd.Spec.Names.Plural,
-		List:    crd.Spec.Names.ListKind,
-		group:   crd.Spec.Group,
-		version: version,
-		Structs: make(map[string]*StructDef),
-		Imports: map[string]bool{`metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`: true},
+		Kind:       crd.Spec.Names.Kind,
+		Plural:     crd.Spec.Names.Plural,
+		List:       crd.Spec.Names.ListKind,
+		Storage:    crdVersion.Storage,
+		Namespaced: crd.Spec.Scope != apiv1.ClusterScoped,
+		group:      crd.Spec.Group,
+		version:    crdVersion.Name,
+		Structs:    make(map[string]*StructDef),
+		Enums:      make(map[string]*EnumTypeDef),
+		Imports:    map[string]bool{`metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`: true},
+	}
+	if sub := crdVersion.Subresources; sub != nil {
+		if sub.Status != nil {
+			cr.Subresources = append(cr.Subresources, "status")
+		}
+		if sub.Scale != nil {
+			cr.Subresources = append(cr.Subresources, "scale")
+		}
 	}
 
 	// Generate structs
-	r.generateStructs(schema, cr, cr.Kind, cr.Kind, true)
-	return cr, nil
+	errs := r.generateStructs(crdVersion.Schema.OpenAPIV3Schema, cr, cr.Kind, cr.Kind, true)
+	cr.Root.Markers = append(resourceMarkers(crd, crdVersion), cr.Root.Markers...)
+	return cr, errs
 }
 
 // Process schema and generate structs.
-func (r *CustomResources) generateStructs(schema *apiv1.JSONSchemaProps, cr *CustomResource, name, path string, root bool) {
+// Properties that can not be generated are reported with their schema path, the remaining properties are processed.
+func (r *CustomResources) generateStructs(
+	schema *apiv1.JSONSchemaProps,
+	cr *CustomResource,
+	name, path string,
+	root bool,
+) (errs []*ParseError) {
 	structDef := &StructDef{
 		Root:        root,
 		Name:        name,
@@ -216,6 +375,10 @@ func (r *CustomResources) generateStructs(schema *apiv1.JSONSchemaProps, cr *Cus
 	}
 	if root {
 		cr.Root = structDef
+		structDef.Validations = celRules(schema, "")
+		for _, rule := range structDef.Validations {
+			structDef.Markers = append(structDef.Markers, validationMarker+"XValidation:"+rule.marker())
+		}
 	} else {
 		cr.Structs[name] = structDef
 		structDef.Path = strings.Join(strings.Split(path, ".")[1:], ".")
@@ -223,12 +386,40 @@ func (r *CustomResources) generateStructs(schema *apiv1.JSONSchemaProps, cr *Cus
 
 	for _, propName := range slices.Sorted(maps.Keys(schema.Properties)) {
 		prop := schema.Properties[propName]
-		fieldName := ToCamelCase(propName)
+		overrideType, overridden := r.typeOverride(cr, schemaPath(path, propName), "")
+		if !overridden {
+			if err := checkProperty(&prop); err != nil {
+				errs = append(errs, &ParseError{Path: schemaPath(path, propName), Err: err})
+				continue
+			}
+		}
+		fieldName := r.names.fieldName(schemaPath(path, propName), propName)
+		if slices.ContainsFunc(structDef.Fields, func(f FieldDef) bool { return f.Name == fieldName }) {
+			errs = append(errs, &ParseError{
+				Path: schemaPath(path, propName),
+				Err:  fmt.Errorf("%w: %s", ErrDuplicateFieldName, fieldName),
+			})
+			continue
+		}
 		var fieldType string
 		field := FieldDef{
 			Name:        fieldName,
 			JSONTag:     propName,
 			Description: prop.Description,
+			Markers:     validationMarkers(&prop),
+			Required:    slices.Contains(schema.Required, propName),
+			Validations: propertyCELRules(&prop),
+		}
+		if prop.Default != nil {
+			field.Default = string(prop.Default.Raw)
+		}
+
+		if overridden {
+			// the override type is used as is, the subtree of the property is not generated
+			field.Type = overrideType
+			field.NoPointer = true
+			structDef.Fields = append(structDef.Fields, field)
+			continue
 		}
 
 		if prop.Type != "" { //nolint:gocritic
@@ -238,13 +429,16 @@ func (r *CustomResources) generateStructs(schema *apiv1.JSONSchemaProps, cr *Cus
 			switch prop.Type {
 			case "object":
 				if len(prop.Properties) > 0 {
-					fieldType = r.generateStructProperty(cr, &prop, fieldName, path, propName, root)
+					var propErrs []*ParseError
+					fieldType, propErrs = r.generateStructProperty(cr, &prop, fieldName, path, propName, root)
+					errs = append(errs, propErrs...)
 				} else {
 					switch {
 					case prop.AdditionalProperties != nil && prop.AdditionalProperties.Schema != nil:
 						additional := mapType(&field, *prop.AdditionalProperties.Schema, cr)
 						if additional == "map[string]any" {
-							additional = r.generateStructProperty(
+							var propErrs []*ParseError
+							additional, propErrs = r.generateStructProperty(
 								cr,
 								prop.AdditionalProperties.Schema,
 								fieldName,
@@ -252,6 +446,7 @@ func (r *CustomResources) generateStructs(schema *apiv1.JSONSchemaProps, cr *Cus
 								propName,
 								root,
 							)
+							errs = append(errs, propErrs...)
 						}
 						fieldType = "map[string]" + additional
 					case propName != "metadata":
@@ -263,7 +458,9 @@ func (r *CustomResources) generateStructs(schema *apiv1.JSONSchemaProps, cr *Cus
 				}
 			case "array":
 				if prop.Items != nil && prop.Items.Schema != nil && prop.Items.Schema.Type == "object" {
-					fieldType = "[]" + r.generateStructProperty(cr, prop.Items.Schema, fieldName, path, propName, root)
+					itemType, propErrs := r.generateStructProperty(cr, prop.Items.Schema, fieldName, path, propName, root)
+					fieldType = "[]" + itemType
+					errs = append(errs, propErrs...)
 				}
 			default:
 				fieldType = mapType(&field, prop, cr)
@@ -272,74 +469,211 @@ func (r *CustomResources) generateStructs(schema *apiv1.JSONSchemaProps, cr *Cus
 			// Handle references
 			parts := strings.Split(*prop.Ref, "/")
 			fieldType = ToCamelCase(parts[len(parts)-1])
-		} else if prop.XIntOrString {
-			fieldType = "intstr.IntOrString"
-			cr.Imports[`"k8s.io/apimachinery/pkg/util/intstr"`] = true
+		} else if t, ok := intOrStringType(&prop, cr); ok {
+			fieldType = t
 		} else {
 			fieldType = "apiextensionsv1.JSON"
 			cr.Imports[`apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"`] = true
 		}
 
-		if prop.Items != nil && len(prop.Items.Schema.Enum) > 0 {
-			fieldType = "[]" + r.generateEnumStruct(cr, prop.Items.Schema, fieldName, &field, path)
-		} else if len(prop.Enum) > 0 {
-			fieldType = r.generateEnumStruct(cr, &prop, fieldName, &field, path)
+		var enumErr error
+		if prop.Items != nil && prop.Items.Schema != nil && len(prop.Items.Schema.Enum) > 0 {
+			var enumType string
+			enumType, enumErr = r.generateEnumStruct(
+				cr, prop.Items.Schema, fieldName, strings.TrimPrefix(fieldType, "[]"), path,
+			)
+			fieldType = "[]" + enumType
+		} else if len(prop.Enum) > 0 && !(root && (propName == "apiVersion" || propName == "kind")) {
+			fieldType, enumErr = r.generateEnumStruct(cr, &prop, fieldName, fieldType, path)
+		}
+		if enumErr != nil {
+			errs = append(errs, &ParseError{Path: schemaPath(path, propName), Err: enumErr})
+			continue
 		}
 
 		field.Type = fieldType
 
 		structDef.Fields = append(structDef.Fields, field)
 	}
+	return errs
 }
 
+// checkProperty checks if the type of property and its array items can be generated.
+func checkProperty(prop *apiv1.JSONSchemaProps) error {
+	switch prop.Type {
+	case "", "object", "array", "string", "integer", "number", "boolean":
+	default:
+		return fmt.Errorf("%w %q", ErrUnsupportedType, prop.Type)
+	}
+	if err := checkDefault(prop); err != nil {
+		return err
+	}
+	if prop.Items == nil {
+		return nil
+	}
+	if prop.Items.Schema == nil {
+		if len(prop.Items.JSONSchemas) > 0 {
+			return ErrUnsupportedItems
+		}
+		return nil
+	}
+	return checkProperty(prop.Items.Schema)
+}
+
+// checkDefault checks if the default value is valid json of the schema type.
+func checkDefault(prop *apiv1.JSONSchemaProps) error {
+	if prop.Default == nil || prop.Type == "" {
+		return nil
+	}
+	var value any
+	// integral numbers are decoded as int64
+	if err := json.Unmarshal(prop.Default.Raw, &value); err != nil {
+		return fmt.Errorf("%w %s: %w", ErrInvalidDefault, prop.Default.Raw, err)
+	}
+	var valid bool
+	switch value.(type) {
+	case string:
+		valid = prop.Type == "string"
+	case bool:
+		valid = prop.Type == "boolean"
+	case int64:
+		valid = prop.Type == "integer" || prop.Type == "number"
+	case float64:
+		valid = prop.Type == "number"
+	case []any:
+		valid = prop.Type == "array"
+	case map[string]any:
+		valid = prop.Type == "object"
+	default:
+		// null resets the field to its zero value
+		valid = true
+	}
+	if !valid {
+		return fmt.Errorf("%w %s: not of type %s", ErrInvalidDefault, prop.Default.Raw, prop.Type)
+	}
+	return nil
+}
+
+// schemaPath returns the path of a property in the openAPIV3Schema, without the kind prefix of the struct path.
+func schemaPath(path, propName string) string {
+	segments := append(strings.Split(path, ".")[1:], propName)
+	return strings.Join(segments, ".")
+}
+
+// generateEnumStruct creates the enum type of a property with the field type as underlying type.
+// If the field type can not be the underlying type of constants or the enum has only null values,
+// it is returned unchanged.
 func (r *CustomResources) generateEnumStruct(
 	cr *CustomResource,
 	prop *apiv1.JSONSchemaProps,
 	fieldName string,
-	field *FieldDef,
+	fieldType string,
 	path string,
-) (fieldType string) {
-	hash := getHash(prop.Enum)
-	if ft, ok := r.structHashes[hash]; ok {
-		fieldType = ft
-	} else {
-		uniqFieldName := r.newUniqFieldName(cr, fieldName, false, path)
-		field.Enums = generateEnum(prop, uniqFieldName)
-		field.EnumType = mapType(field, *prop, cr)
-		field.EnumName = uniqFieldName
-		fieldType = uniqFieldName
-		r.structHashes[hash] = uniqFieldName
+) (string, error) {
+	if !isEnumBaseType(fieldType) || !hasEnumValues(prop) {
+		return fieldType, nil
+	}
+	// enums with the same values but a different underlying type are different types
+	key := r.hashKey(cr, fieldName, path, getHash([]any{fieldType, prop.Enum}))
+	if ft, ok := r.structHashes[key]; ok {
+		return ft, nil
+	}
+	uniqFieldName, err := r.newUniqFieldName(cr, fieldName, false, path)
+	if err != nil {
+		return "", err
+	}
+	values, err := r.names.generateEnum(prop, uniqFieldName, fieldType)
+	if err != nil {
+		return "", err
 	}
-	return fieldType
+	cr.Enums[uniqFieldName] = &EnumTypeDef{
+		Name:   uniqFieldName,
+		Type:   fieldType,
+		Field:  fieldName,
+		Values: values,
+	}
+	cr.Imports[`"encoding/json"`] = true
+	cr.Imports[`"fmt"`] = true
+	r.structHashes[key] = uniqFieldName
+	return uniqFieldName, nil
 }
 
-func (r *CustomResources) newUniqFieldName(cr *CustomResource, fieldName string, root bool, path string) string {
-	name := fieldName
-	if !root { // root structs should have kind prefix
-		if _, ok := r.structNames[name]; !ok {
-			r.structNames[name] = true
-			return name
+// hashKey returns the key of a generated struct or enum type in the struct hashes.
+// With the shortest naming strategy, types with the same schema are reused in the whole package.
+// Otherwise, they are only reused for properties of the same kind that get the same name,
+// so the names do not depend on the other CRDs and properties.
+func (r *CustomResources) hashKey(cr *CustomResource, fieldName, path, hash string) string {
+	if r.naming == NamingShortest {
+		return hash
+	}
+	return cr.Kind + "/" + r.stableName(cr, fieldName, path) + "/" + hash
+}
+
+// newUniqFieldName returns a new unique name for the struct or enum type of a property.
+// If the name of the path or kind naming strategy is already used, an ErrStructNameCollision is returned.
+func (r *CustomResources) newUniqFieldName(
+	cr *CustomResource,
+	fieldName string,
+	root bool,
+	path string,
+) (string, error) {
+	name, err := r.freeFieldName(cr, fieldName, root, path)
+	if err != nil {
+		return "", err
+	}
+	r.structNames[name] = true
+	return name, nil
+}
+
+// freeFieldName returns the first name of the naming strategy that is not used yet, without reserving it.
+// If all names of the shortest naming strategy are used, the field name with the hash of the path is returned.
+func (r *CustomResources) freeFieldName(cr *CustomResource, fieldName string, root bool, path string) (string, error) {
+	candidates := []string{r.stableName(cr, fieldName, path)}
+	if r.naming == NamingShortest {
+		candidates = r.shortestNames(cr, fieldName, root, path)
+	}
+	for _, name := range candidates {
+		if !r.structNames[name] {
+			return name, nil
 		}
 	}
-	name = cr.Kind + fieldName
-	if _, ok := r.structNames[name]; !ok {
-		r.structNames[name] = true
-		return name
+	if r.naming == NamingShortest {
+		hash := md5.Sum([]byte(path + "." + fieldName))
+		return fieldName + "_" + hex.EncodeToString(hash[:]), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrStructNameCollision, candidates[len(candidates)-1])
+}
+
+// shortestNames returns the name candidates of the shortest naming strategy, from the field name
+// to the field name prefixed with the kind and all parent properties.
+func (r *CustomResources) shortestNames(cr *CustomResource, fieldName string, root bool, path string) []string {
+	var names []string
+	if !root { // root structs should have kind prefix
+		names = append(names, fieldName)
 	}
+	names = append(names, cr.Kind+fieldName)
 
 	paths := strings.Split(path, ".")
 	var prefix string
 	for i := len(paths) - 1; i >= 0; i-- {
-		prefix = ToCamelCase(paths[i]) + prefix
-		name = prefix + fieldName
-		if _, ok := r.structNames[name]; !ok {
-			r.structNames[name] = true
-			return name
-		}
+		prefix = r.names.camelCase(paths[i]) + prefix
+		names = append(names, prefix+fieldName)
 	}
+	return names
+}
 
-	hash := md5.Sum([]byte(path + "." + fieldName))
-	return fieldName + "_" + hex.EncodeToString(hash[:])
+// stableName returns the name of the path or kind naming strategy, which only depends on the kind and
+// the schema path of the property.
+func (r *CustomResources) stableName(cr *CustomResource, fieldName, path string) string {
+	if r.naming == NamingKind {
+		return cr.Kind + fieldName
+	}
+	name := cr.Kind
+	segments := strings.Split(path, ".")[1:]
+	for i, segment := range segments {
+		name += r.names.fieldName(strings.Join(segments[:i+1], "."), segment)
+	}
+	return name + fieldName
 }
 
 func (r *CustomResources) generateStructProperty(
@@ -347,56 +681,74 @@ func (r *CustomResources) generateStructProperty(
 	prop *apiv1.JSONSchemaProps,
 	fieldName, path, propName string,
 	root bool,
-) (fieldType string) {
-	hash := getHash(prop.Properties)
+) (fieldType string, errs []*ParseError) {
+	if !root {
+		// the spec and status of a kind are always generated
+		if kt, ok := findKnownType(r.knownTypes, prop, propName); ok {
+			cr.Imports[kt.Import()] = true
+			return kt.GoType(), nil
+		}
+	}
+
+	key := r.hashKey(cr, fieldName, path, getHash(prop.Properties))
+	// overrides are scoped to the kind, also if the shortest naming strategy shares the structs between kinds
+	overrideKey := "override/" + cr.Kind + "/" + key
 
-	if ft, ok := r.structHashes[hash]; ok {
+	if ft, ok := r.structHashes[overrideKey]; ok {
+		fieldType = ft
+	} else if ft, ok := r.structHashes[key]; ok {
 		fieldType = ft
 	} else {
 		// Check if the current property is a metav1.Condition
 		if isMetav1Condition(prop) {
 			cr.Imports[`metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`] = true
-			return "metav1.Condition"
+			return "metav1.Condition", nil
 		}
 
-		uniqFieldName := r.newUniqFieldName(cr, fieldName, root, path)
+		// the override is checked before the name is reserved, so the next property of the same schema
+		// does not get a new name that is not overridden
+		uniqFieldName, err := r.freeFieldName(cr, fieldName, root, path)
+		if err != nil {
+			return "", []*ParseError{{Path: schemaPath(path, propName), Err: err}}
+		}
+		if overrideType, ok := r.typeOverride(cr, "", uniqFieldName); ok {
+			r.structHashes[overrideKey] = overrideType
+			return overrideType, nil
+		}
+		r.structNames[uniqFieldName] = true
 		fieldType = uniqFieldName
-		r.structHashes[hash] = uniqFieldName
-		r.generateStructs(prop, cr, uniqFieldName, path+"."+propName, false)
+		r.structHashes[key] = uniqFieldName
+		errs = r.generateStructs(prop, cr, uniqFieldName, path+"."+propName, false)
 	}
-	return fieldType
+	return fieldType, errs
 }
 
-// ToCamelCase convert string to CamelCase.
-func ToCamelCase(s string) string {
-	words := strings.FieldsFunc(s, func(r rune) bool {
-		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
-	})
-
-	for i, word := range words {
-		if word != "" {
-			words[i] = strings.ToUpper(string(word[0])) + word[1:]
-		}
+// selectVersions selects the versions of the CRD to be generated.
+// If no selector is defined, the storage version is selected.
+func selectVersions(
+	crd *apiv1.CustomResourceDefinition,
+	selector []string,
+) (selected []apiv1.CustomResourceDefinitionVersion, err error) {
+	if len(selector) == 0 {
+		selector = []string{VersionsStorage}
 	}
 
-	return strings.Join(words, "")
-}
-
-// Extract schemas from CRD.
-func extractSchemas(
-	crd apiv1.CustomResourceDefinition,
-	desiredVersion string,
-) (schema *apiv1.JSONSchemaProps, version string, err error) {
-	// Try to get schema from new CRD format first (v1)
-	if len(crd.Spec.Versions) > 0 {
-		for _, v := range crd.Spec.Versions {
-			if v.Storage && (desiredVersion == "" || desiredVersion == v.Name) {
-				return v.Schema.OpenAPIV3Schema, v.Name, nil
+	for _, v := range crd.Spec.Versions {
+		for _, sel := range selector {
+			if sel == VersionsAll ||
+				(sel == VersionsServed && v.Served) ||
+				(sel == VersionsStorage && v.Storage) ||
+				sel == v.Name {
+				selected = append(selected, v)
+				break
 			}
 		}
 	}
 
-	return nil, "", fmt.Errorf("could not find desired version %q in CRD", desiredVersion)
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrVersionNotFound, selector)
+	}
+	return selected, nil
 }
 
 func isMetav1Condition(schema *apiv1.JSONSchemaProps) bool {
@@ -428,6 +780,39 @@ func isMetav1Condition(schema *apiv1.JSONSchemaProps) bool {
 	return true
 }
 
+// quantityPattern is the pattern controller-gen emits for resource.Quantity.
+const quantityPattern = `^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))` +
+	`(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+
+// intOrStringType maps int-or-string schemas to resource.Quantity if the schema has the quantity pattern,
+// otherwise x-kubernetes-int-or-string schemas to intstr.IntOrString.
+// Other anyOf schemas are not mapped.
+func intOrStringType(prop *apiv1.JSONSchemaProps, cr *CustomResource) (string, bool) {
+	if isQuantity(prop) {
+		cr.Imports[`"k8s.io/apimachinery/pkg/api/resource"`] = true
+		return "resource.Quantity", true
+	}
+	if !prop.XIntOrString {
+		return "", false
+	}
+	cr.Imports[`"k8s.io/apimachinery/pkg/util/intstr"`] = true
+	return "intstr.IntOrString", true
+}
+
+// isQuantity checks if the schema is the int-or-string schema of a resource.Quantity.
+func isQuantity(prop *apiv1.JSONSchemaProps) bool {
+	return prop.Pattern == quantityPattern && (prop.XIntOrString || isIntOrStringAnyOf(prop))
+}
+
+// isIntOrStringAnyOf checks if the schema is an anyOf of an integer and a string schema.
+func isIntOrStringAnyOf(prop *apiv1.JSONSchemaProps) bool {
+	if prop.Type != "" || len(prop.AnyOf) != 2 {
+		return false
+	}
+	types := []string{prop.AnyOf[0].Type, prop.AnyOf[1].Type}
+	return slices.Contains(types, "integer") && slices.Contains(types, "string")
+}
+
 // Helper function to map OpenAPI types to Go types.
 func mapType(field *FieldDef, prop apiv1.JSONSchemaProps, cr *CustomResource) string {
 	if prop.Type == "" {
@@ -435,9 +820,8 @@ func mapType(field *FieldDef, prop apiv1.JSONSchemaProps, cr *CustomResource) st
 			parts := strings.Split(*prop.Ref, "/")
 			return ToCamelCase(parts[len(parts)-1])
 		}
-		if prop.XIntOrString {
-			cr.Imports[`"k8s.io/apimachinery/pkg/util/intstr"`] = true
-			return "intstr.IntOrString"
+		if t, ok := intOrStringType(&prop, cr); ok {
+			return t
 		}
 		return "any"
 	}
@@ -496,28 +880,98 @@ func mapType(field *FieldDef, prop apiv1.JSONSchemaProps, cr *CustomResource) st
 	}
 }
 
+// isEnumBaseType checks if enum constants can be generated for the go type of a property.
+func isEnumBaseType(goType string) bool {
+	switch goType {
+	case "string", "bool", "int32", "int64", "float32", "float64":
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeEnumValue decodes a raw enum value, whole numbers are decoded as int64.
+// Invalid values are decoded as nil like null.
+func decodeEnumValue(raw []byte) any {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+// hasEnumValues checks if the enum of a property has a value that is not null.
+func hasEnumValues(prop *apiv1.JSONSchemaProps) bool {
+	return slices.ContainsFunc(prop.Enum, func(e apiv1.JSON) bool { return decodeEnumValue(e.Raw) != nil })
+}
+
+// enumLiteral returns the go literal of a raw enum value of the base type.
+func enumLiteral(raw []byte, baseType string) (string, error) {
+	switch v := decodeEnumValue(raw).(type) {
+	case string:
+		if baseType == "string" {
+			return strconv.Quote(v), nil
+		}
+	case bool:
+		if baseType == "bool" {
+			return strconv.FormatBool(v), nil
+		}
+	case int64:
+		switch baseType {
+		case "int32":
+			if v >= math.MinInt32 && v <= math.MaxInt32 {
+				return strconv.FormatInt(v, 10), nil
+			}
+		case "int64", "float32", "float64":
+			return strconv.FormatInt(v, 10), nil
+		default:
+		}
+	case float64:
+		if baseType == "float32" || baseType == "float64" {
+			return strconv.FormatFloat(v, 'g', -1, 64), nil
+		}
+	default:
+	}
+	return "", fmt.Errorf("%w: value %s is not a %s", ErrInvalidEnum, raw, baseType)
+}
+
 // createEnumName creates a cleaned and formatted enum name by combining field name and suffix.
-func createEnumName(fieldName, enumValue string) string {
+func (n namer) createEnumName(fieldName, enumValue string) string {
 	cleanedValue := strings.ReplaceAll(enumValue, `"`, "")
-	switch cleanedValue {
-	case wildcardEnum:
+	switch {
+	case cleanedValue == wildcardEnum:
 		cleanedValue = wildcardReplace
-	case "":
+	case cleanedValue == "":
 		cleanedValue = enumEmptyValue
+	case !strings.HasPrefix(enumValue, `"`):
+		// the sign and decimal point of numbers would be dropped
+		cleanedValue = numberReplacer.Replace(cleanedValue)
 	default:
 	}
-	return fieldName + ToCamelCase(cleanedValue)
+	return fieldName + n.camelCase(cleanedValue)
 }
 
-func generateEnum(prop *apiv1.JSONSchemaProps, fieldName string) (enums []EnumDef) {
+// numberReplacer replaces the characters of numbers that are not valid in go names.
+var numberReplacer = strings.NewReplacer("-", "Minus", "+", "", ".", "Point")
+
+// generateEnum creates the constants of the enum values. Null values are skipped.
+func (n namer) generateEnum(prop *apiv1.JSONSchemaProps, fieldName, baseType string) ([]EnumDef, error) {
+	var enums []EnumDef
 	for _, enumRaw := range prop.Enum {
-		enumValue := string(enumRaw.Raw)
-		enums = append(enums, EnumDef{
-			Name:  createEnumName(fieldName, enumValue),
-			Value: enumValue,
-		})
+		if decodeEnumValue(enumRaw.Raw) == nil {
+			continue
+		}
+		value, err := enumLiteral(enumRaw.Raw, baseType)
+		if err != nil {
+			return nil, err
+		}
+		name := n.createEnumName(fieldName, value)
+		if slices.ContainsFunc(enums, func(e EnumDef) bool { return e.Name == name }) {
+			return nil, fmt.Errorf("%w: duplicate enum constant %s", ErrInvalidEnum, name)
+		}
+		enums = append(enums, EnumDef{Name: name, Value: value})
 	}
-	return enums
+	return enums, nil
 }
 
 func getHash(y any) string {
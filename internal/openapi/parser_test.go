@@ -1,9 +1,13 @@
 package openapi
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 func Test_newUniqFieldName(t *testing.T) {
@@ -14,20 +18,292 @@ func Test_newUniqFieldName(t *testing.T) {
 	r := &CustomResources{
 		structNames: make(map[string]bool),
 	}
-	un := r.newUniqFieldName(cr, "Spec", true, "TestCase")
-	assert.Equal(t, "TestCaseSpec", un)
-	un = r.newUniqFieldName(cr, "Status", true, "TestCase")
-	assert.Equal(t, "TestCaseStatus", un)
-	un = r.newUniqFieldName(cr, "Foo", false, "TestCase.Spec")
-	assert.Equal(t, "Foo", un)
-	un = r.newUniqFieldName(cr, "Foo", false, "TestCase.Status")
-	assert.Equal(t, "TestCaseFoo", un)
-	un = r.newUniqFieldName(cr, "Foo", false, "TestCase.Spec.Bar")
-	assert.Equal(t, "BarFoo", un)
-	un = r.newUniqFieldName(cr, "Foo", false, "TestCase.Status.Bar")
-	assert.Equal(t, "StatusBarFoo", un)
-	un = r.newUniqFieldName(cr, "Foo", false, "TestCase.Status.Bar")
-	assert.Equal(t, "TestCaseStatusBarFoo", un)
-	un = r.newUniqFieldName(cr, "Foo", false, "TestCase.Status.Bar")
-	assert.Equal(t, "Foo_f8559662a4db3e0bf226e9df87cdcfb1", un)
+	uniqName := func(fieldName string, root bool, path string) string {
+		t.Helper()
+		un, err := r.newUniqFieldName(cr, fieldName, root, path)
+		require.NoError(t, err)
+		return un
+	}
+	assert.Equal(t, "TestCaseSpec", uniqName("Spec", true, "TestCase"))
+	assert.Equal(t, "TestCaseStatus", uniqName("Status", true, "TestCase"))
+	assert.Equal(t, "Foo", uniqName("Foo", false, "TestCase.Spec"))
+	assert.Equal(t, "TestCaseFoo", uniqName("Foo", false, "TestCase.Status"))
+	assert.Equal(t, "BarFoo", uniqName("Foo", false, "TestCase.Spec.Bar"))
+	assert.Equal(t, "StatusBarFoo", uniqName("Foo", false, "TestCase.Status.Bar"))
+	assert.Equal(t, "TestCaseStatusBarFoo", uniqName("Foo", false, "TestCase.Status.Bar"))
+	assert.Equal(t, "Foo_f8559662a4db3e0bf226e9df87cdcfb1", uniqName("Foo", false, "TestCase.Status.Bar"))
+}
+
+func Test_newUniqFieldName_stable(t *testing.T) {
+	cr := &CustomResource{
+		Kind: "TestCase",
+	}
+
+	for naming, expected := range map[NamingStrategy][]string{
+		NamingPath: {"TestCaseSpec", "TestCaseSpecFoo", "TestCaseStatusFoo", "TestCaseSpecBarFoo"},
+		NamingKind: {"TestCaseSpec", "TestCaseFoo"},
+	} {
+		r := &CustomResources{
+			structNames: make(map[string]bool),
+			naming:      naming,
+		}
+		var names []string
+		for _, path := range []string{"TestCase", "TestCase.spec", "TestCase.status", "TestCase.spec.bar"} {
+			fieldName := "Foo"
+			if path == "TestCase" {
+				fieldName = "Spec"
+			}
+			un, err := r.newUniqFieldName(cr, fieldName, path == "TestCase", path)
+			if err != nil {
+				require.ErrorIs(t, err, ErrStructNameCollision, naming)
+				continue
+			}
+			names = append(names, un)
+		}
+		assert.Equal(t, expected, names, naming)
+	}
+}
+
+func Test_ParseDefinitions_kindNames(t *testing.T) {
+	crd := func(kind string) Definition {
+		return Definition{CRD: &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+			Group: "testing.crd-gen",
+			Names: apiv1.CustomResourceDefinitionNames{Kind: kind, ListKind: kind + "List"},
+			Versions: []apiv1.CustomResourceDefinitionVersion{{
+				Name: "v1", Served: true, Storage: true,
+				Schema: &apiv1.CustomResourceValidation{OpenAPIV3Schema: &apiv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiv1.JSONSchemaProps{
+						"spec": {Type: "object", Properties: map[string]apiv1.JSONSchemaProps{
+							"foo":     {Type: "object", Properties: map[string]apiv1.JSONSchemaProps{"a": {Type: "string"}}},
+							"barList": {Type: "object", Properties: map[string]apiv1.JSONSchemaProps{"b": {Type: "string"}}},
+						}},
+					},
+				}},
+			}},
+		}}}
+	}
+
+	// the structs of Foo are processed before the kind Bar
+	res, err := ParseDefinitions([]Definition{crd("Foo"), crd("Bar")}, Options{})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.ElementsMatch(t, []string{"FooSpec", "FooFoo", "FooBarList"},
+		slices.Collect(maps.Keys(res[0].Items[0].Structs)), "structs must not get the name of a kind or list")
+}
+
+func Test_selectVersions(t *testing.T) {
+	crd := &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+		Versions: []apiv1.CustomResourceDefinitionVersion{
+			{Name: "v1alpha1"},
+			{Name: "v1beta1", Served: true},
+			{Name: "v1", Served: true, Storage: true},
+		},
+	}}
+
+	testCases := []struct {
+		selector []string
+		want     []string
+		wantErr  bool
+	}{
+		{selector: nil, want: []string{"v1"}},
+		{selector: []string{VersionsStorage}, want: []string{"v1"}},
+		{selector: []string{VersionsServed}, want: []string{"v1beta1", "v1"}},
+		{selector: []string{VersionsAll}, want: []string{"v1alpha1", "v1beta1", "v1"}},
+		{selector: []string{"v1alpha1", "v1"}, want: []string{"v1alpha1", "v1"}},
+		{selector: []string{"v2"}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		selected, err := selectVersions(crd, tc.selector)
+		if tc.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		var names []string
+		for _, v := range selected {
+			names = append(names, v.Name)
+		}
+		assert.Equal(t, tc.want, names, "selector %v", tc.selector)
+	}
+}
+
+func Test_generateStructs_errors(t *testing.T) {
+	r := &CustomResources{
+		structHashes: make(map[string]string),
+		structNames:  make(map[string]bool),
+	}
+	crd := &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+		Names: apiv1.CustomResourceDefinitionNames{Kind: "Broken"},
+	}}
+	cr, errs := r.parseCRD(crd, &apiv1.CustomResourceDefinitionVersion{
+		Name: "v1",
+		Schema: &apiv1.CustomResourceValidation{OpenAPIV3Schema: &apiv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]apiv1.JSONSchemaProps{
+						"valid": {Type: "string"},
+						"list": {Type: "array", Items: &apiv1.JSONSchemaPropsOrArray{
+							Schema: &apiv1.JSONSchemaProps{Type: "decimal"},
+						}},
+					},
+				},
+			},
+		}},
+	})
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "spec.list", errs[0].Path)
+	require.ErrorIs(t, errs[0], ErrUnsupportedType)
+	assert.Len(t, cr.Structs["BrokenSpec"].Fields, 1)
+
+	_, errs = r.parseCRD(crd, &apiv1.CustomResourceDefinitionVersion{Name: "v1"})
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrNoSchema)
+}
+
+func Test_generateStructs_nullEnum(t *testing.T) {
+	r := &CustomResources{
+		structHashes: make(map[string]string),
+		structNames:  make(map[string]bool),
+	}
+	crd := &apiv1.CustomResourceDefinition{Spec: apiv1.CustomResourceDefinitionSpec{
+		Names: apiv1.CustomResourceDefinitionNames{Kind: "Nullable"},
+	}}
+	cr, errs := r.parseCRD(crd, &apiv1.CustomResourceDefinitionVersion{
+		Name: "v1",
+		Schema: &apiv1.CustomResourceValidation{OpenAPIV3Schema: &apiv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]apiv1.JSONSchemaProps{
+						"mode": {Type: "string", Nullable: true, Enum: []apiv1.JSON{{Raw: []byte("null")}}},
+					},
+				},
+			},
+		}},
+	})
+
+	require.Empty(t, errs)
+	assert.Empty(t, cr.Enums, "enums with only null values do not get a type")
+	require.Len(t, cr.Structs["NullableSpec"].Fields, 1)
+	assert.Equal(t, "string", cr.Structs["NullableSpec"].Fields[0].Type)
+}
+
+func Test_checkDefault(t *testing.T) {
+	prop := func(typ, value string) *apiv1.JSONSchemaProps {
+		return &apiv1.JSONSchemaProps{Type: typ, Default: &apiv1.JSON{Raw: []byte(value)}}
+	}
+	for _, p := range []*apiv1.JSONSchemaProps{
+		prop("string", `"TCP"`),
+		prop("integer", `8080`),
+		prop("number", `8080`),
+		prop("number", `0.5`),
+		prop("boolean", `true`),
+		prop("array", `["a"]`),
+		prop("object", `{}`),
+		prop("", `"80%"`),
+		{Type: "string"},
+	} {
+		require.NoError(t, checkDefault(p), "%s", p.Default)
+	}
+	for _, p := range []*apiv1.JSONSchemaProps{
+		prop("integer", `"8080"`),
+		prop("integer", `0.5`),
+		prop("string", `8080`),
+		prop("boolean", `"true"`),
+		prop("array", `{}`),
+		prop("object", `invalid`),
+	} {
+		require.ErrorIs(t, checkDefault(p), ErrInvalidDefault, "%s", p.Default.Raw)
+	}
+}
+
+func Test_ParseError(t *testing.T) {
+	err := &ParseError{Input: "crds.yaml", CRD: "widgets.testing", Version: "v1", Path: "spec.size", Err: ErrUnsupportedType}
+	assert.EqualError(t, err, `input "crds.yaml", crd "widgets.testing", version "v1", path "spec.size": unsupported schema type`)
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	assert.EqualError(t, &ParseError{Err: ErrNoCRDFound}, "no CRD found")
+	assert.EqualError(t, &ParseError{CRD: "widgets.testing"}, `crd "widgets.testing": parse error`)
+	assert.EqualError(t, &ParseError{}, "parse error")
+}
+
+func Test_intOrStringType(t *testing.T) {
+	intOrString := []apiv1.JSONSchemaProps{{Type: "integer"}, {Type: "string"}}
+
+	cr := &CustomResource{Imports: make(map[string]bool)}
+	typ, ok := intOrStringType(&apiv1.JSONSchemaProps{
+		AnyOf:        intOrString,
+		Pattern:      quantityPattern,
+		XIntOrString: true,
+	}, cr)
+	assert.True(t, ok)
+	assert.Equal(t, "resource.Quantity", typ)
+	assert.Equal(t, map[string]bool{`"k8s.io/apimachinery/pkg/api/resource"`: true}, cr.Imports)
+
+	// controller-gen may emit the quantity as anyOf only
+	typ, ok = intOrStringType(&apiv1.JSONSchemaProps{AnyOf: intOrString, Pattern: quantityPattern}, cr)
+	assert.True(t, ok)
+	assert.Equal(t, "resource.Quantity", typ)
+
+	cr = &CustomResource{Imports: make(map[string]bool)}
+	typ, ok = intOrStringType(&apiv1.JSONSchemaProps{XIntOrString: true, Pattern: "^[0-9]+%?$"}, cr)
+	assert.True(t, ok)
+	assert.Equal(t, "intstr.IntOrString", typ)
+	assert.Equal(t, map[string]bool{`"k8s.io/apimachinery/pkg/util/intstr"`: true}, cr.Imports)
+
+	_, ok = intOrStringType(&apiv1.JSONSchemaProps{AnyOf: intOrString}, cr)
+	assert.False(t, ok, "anyOf schemas without the quantity pattern are not mapped")
+
+	_, ok = intOrStringType(&apiv1.JSONSchemaProps{
+		AnyOf: []apiv1.JSONSchemaProps{{Type: "boolean"}, {Type: "string"}},
+	}, cr)
+	assert.False(t, ok)
+
+	assert.Empty(t, validationMarkers(&apiv1.JSONSchemaProps{XIntOrString: true, Pattern: quantityPattern}),
+		"the quantity pattern is implied by the go type")
+}
+
+func Test_generateEnum(t *testing.T) {
+	enumProp := func(values ...string) *apiv1.JSONSchemaProps {
+		prop := &apiv1.JSONSchemaProps{}
+		for _, v := range values {
+			prop.Enum = append(prop.Enum, apiv1.JSON{Raw: []byte(v)})
+		}
+		return prop
+	}
+
+	enums, err := namer{}.generateEnum(enumProp(`"Read"`, `""`, `"*"`, ""), "Mode", "string")
+	require.NoError(t, err)
+	assert.Equal(t, []EnumDef{
+		{Name: "ModeRead", Value: `"Read"`},
+		{Name: "ModeEmptyValue", Value: `""`},
+		{Name: "ModeAll", Value: `"*"`},
+	}, enums, "null values are skipped")
+
+	enums, err = namer{}.generateEnum(enumProp("1", "-1", "1.5"), "Level", "float64")
+	require.NoError(t, err)
+	assert.Equal(t, []EnumDef{
+		{Name: "Level1", Value: "1"},
+		{Name: "LevelMinus1", Value: "-1"},
+		{Name: "Level1Point5", Value: "1.5"},
+	}, enums)
+
+	enums, err = namer{}.generateEnum(enumProp("true", "false"), "Enabled", "bool")
+	require.NoError(t, err)
+	assert.Equal(t, []EnumDef{
+		{Name: "EnabledTrue", Value: "true"},
+		{Name: "EnabledFalse", Value: "false"},
+	}, enums)
+
+	_, err = namer{}.generateEnum(enumProp(`"a"`), "Port", "int32")
+	require.ErrorIs(t, err, ErrInvalidEnum)
+	_, err = namer{}.generateEnum(enumProp("4294967296"), "Port", "int32")
+	require.ErrorIs(t, err, ErrInvalidEnum)
+	_, err = namer{}.generateEnum(enumProp(`"a-b"`, `"aB"`), "Name", "string")
+	require.ErrorIs(t, err, ErrInvalidEnum)
 }
@@ -1,5 +1,80 @@
 package openapi
 
+import (
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// PointerMode defines which struct fields are generated as pointers.
+type PointerMode string
+
+const (
+	// PointerNone generates no pointer fields.
+	PointerNone PointerMode = ""
+	// PointerAll generates all struct fields as pointers.
+	PointerAll PointerMode = "all"
+	// PointerOptional generates optional scalar and struct fields as pointers.
+	PointerOptional PointerMode = "optional"
+)
+
+// NamingStrategy defines how the generated structs and enum types are named.
+type NamingStrategy string
+
+const (
+	// NamingShortest uses the shortest unique name, prefixed with the kind and parent properties on collisions.
+	// The names depend on the order the CRDs and properties are processed in.
+	NamingShortest NamingStrategy = ""
+	// NamingPath prefixes the names with the kind and the full schema path, e.g. WidgetSpecTemplateSpec.
+	NamingPath NamingStrategy = "path"
+	// NamingKind prefixes the names with the kind, e.g. WidgetTemplate.
+	NamingKind NamingStrategy = "kind"
+)
+
+const (
+	// VersionsAll selects all versions of a CRD.
+	VersionsAll = "all"
+	// VersionsServed selects the served versions of a CRD.
+	VersionsServed = "served"
+	// VersionsStorage selects the storage version of a CRD.
+	VersionsStorage = "storage"
+)
+
+// Options defines how the CRDs are parsed.
+type Options struct {
+	// Versions selects the versions of each CRD: VersionsAll, VersionsServed, VersionsStorage or version names.
+	Versions []string
+	// Pointers defines which struct fields are generated as pointers.
+	Pointers PointerMode
+	// Kinds selects the CRDs by kind. If empty, all kinds are selected.
+	Kinds []string
+	// Groups selects the CRDs by API group. If empty, all groups are selected.
+	Groups []string
+	// HelmTemplates enables rendering the templates of Helm chart inputs with the helm binary.
+	HelmTemplates bool
+	// HelmValues are the values files used to render the templates of Helm chart inputs.
+	HelmValues []string
+	// ClientConfig is the kubernetes client config used to read k8s:<name> inputs from a cluster.
+	ClientConfig clientcmd.ClientConfig
+	// KnownTypes are the existing go types used instead of generating structs for matching schemas.
+	KnownTypes []KnownType
+	// TypeOverrides replace the generated types of properties by schema path or struct name.
+	TypeOverrides []TypeOverride
+	// Initialisms are additional initialisms written in upper case in go names, e.g. OIDC.
+	Initialisms []string
+	// FieldNames maps schema paths (spec.apiURL) or property names (apiURL) to explicit go field names.
+	FieldNames map[string]string
+	// Naming defines how the generated structs and enum types are named.
+	Naming NamingStrategy
+}
+
+// Definition is a CRD with the input it was read from.
+type Definition struct {
+	// Input is the CRD input as defined by the user.
+	Input string
+	// CRD is the decoded CRD.
+	CRD *apiv1.CustomResourceDefinition
+}
+
 // SchemaProperty represents a property in an OpenAPI schema.
 type SchemaProperty struct {
 	Type        any            `yaml:"type"`
@@ -16,19 +91,31 @@ type CustomResources struct {
 	Group   string
 	Version string
 
-	structHashes map[string]string
-	structNames  map[string]bool
+	structHashes  map[string]string
+	structNames   map[string]bool
+	knownTypes    []KnownType
+	typeOverrides []TypeOverride
+	names         namer
+	naming        NamingStrategy
 }
 
 type CustomResource struct {
 	Kind    string
 	Root    *StructDef
 	Structs map[string]*StructDef
+	// Enums holds the enum types created for the properties of the kind.
+	Enums   map[string]*EnumTypeDef
 	Imports map[string]bool
 	Plural  string
 	List    string
-	group   string
-	version string
+	// Storage is true if the version of the custom resource is the storage version.
+	Storage bool
+	// Namespaced is true if the custom resource is namespace scoped.
+	Namespaced bool
+	// Subresources holds the enabled subresources of the version: status and scale.
+	Subresources []string
+	group        string
+	version      string
 }
 
 // StructDef represents a Go struct definition.
@@ -38,6 +125,10 @@ type StructDef struct {
 	Description string
 	Root        bool
 	Path        string
+	// Markers holds the kubebuilder markers (without the leading "// +") rendered above the root type.
+	Markers []string
+	// Validations holds the x-kubernetes-validations rules of the root schema.
+	Validations []ValidationRule
 }
 
 // FieldDef represents a field in a Go struct.
@@ -46,15 +137,44 @@ type FieldDef struct {
 	Type          string
 	JSONTag       string
 	Description   string
-	Enums         []EnumDef
-	EnumName      string
-	EnumType      string
 	SkipDeepEqual bool
 	NoPointer     bool
+	// Default is the raw JSON default value of the property.
+	Default string
+	// Validations holds the x-kubernetes-validations rules of the property and its items.
+	Validations []ValidationRule
+	// Required is true if the property is listed in the required properties of the schema.
+	Required bool
+	// Markers holds the kubebuilder markers (without the leading "// +") rendered above the field.
+	Markers []string
+}
+
+// ValidationRule represents a x-kubernetes-validations CEL rule.
+type ValidationRule struct {
+	Rule              string
+	Message           string
+	MessageExpression string
+	Reason            string
+	FieldPath         string
+	// Scope is the JSON path the rule applies to, relative to the property:
+	// "" for the property itself, "[*]" for array items and "{*}" for map values.
+	Scope string
+}
+
+// EnumTypeDef represents a Go type of an enumeration.
+type EnumTypeDef struct {
+	Name string
+	// Type is the underlying go type: string, bool, int32, int64, float32 or float64.
+	Type string
+	// Field is the name of the field the enum type was created for.
+	Field  string
+	Values []EnumDef
 }
 
+// EnumDef represents a constant of an enum type.
 type EnumDef struct {
-	Name  string
+	Name string
+	// Value is the go literal of the enum value.
 	Value string
 }
 
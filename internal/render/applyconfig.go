@@ -0,0 +1,363 @@
+package render
+
+import (
+	"fmt"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+const (
+	applyConfigDir    = "applyconfiguration"
+	applyConfigSuffix = "ApplyConfiguration"
+	typesAlias        = "api"
+	untypedDeduced    = "__untyped_deduced_"
+)
+
+// applyConfigField is a field of an apply configuration with the statements of its With function.
+type applyConfigField struct {
+	Name        string
+	JSONTag     string
+	Description string
+	// Type is the go type of the field: a pointer, slice, map or any.
+	Type string
+	// Param is the parameter of the With function.
+	Param string
+	// Kind is the kind of the With function: set, add or put.
+	Kind       string
+	Statements []string
+}
+
+// applyConfigStruct is the apply configuration of a generated struct.
+type applyConfigStruct struct {
+	Name        string
+	Description string
+	Fields      []applyConfigField
+}
+
+// applyConfigKind is the apply configuration of a kind.
+type applyConfigKind struct {
+	applyConfigStruct
+	// Object is the parameter name of the kind in the extract functions.
+	Object       string
+	APIVersion   string
+	Namespaced   bool
+	Subresources []applyConfigSubresource
+}
+
+// applyConfigSubresource is a subresource with the name of its extract function.
+type applyConfigSubresource struct {
+	Name string
+	Func string
+}
+
+// applyConfigGenerator creates the apply configurations and the merge schema of a group version package.
+type applyConfigGenerator struct {
+	structs map[string]bool
+	// enums maps the enum types to their go base type.
+	enums    map[string]string
+	packages packageSet
+}
+
+// renderApplyConfigurations generates the apply configuration package of each group version package.
+// The packages are located in the applyconfiguration directory of the target directory.
+func renderApplyConfigurations(
+	packages []*openapi.CustomResources,
+	pkgDirs map[*openapi.CustomResources]string,
+	targetDir string,
+	opts Options,
+) ([]File, error) {
+	var files []File
+	for _, res := range packages {
+		rel, err := filepath.Rel(targetDir, pkgDirs[res])
+		if err != nil {
+			return nil, err
+		}
+		typesImport, err := packageImportPath(targetDir, pkgDirs[res], opts.ImportPath)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating import path of version %q: %w", res.Version, err)
+		}
+		pkgDir := filepath.Join(targetDir, applyConfigDir, rel)
+
+		g := &applyConfigGenerator{
+			structs: make(map[string]bool),
+			enums:   make(map[string]string),
+		}
+		for _, cr := range res.Items {
+			for name := range cr.Structs {
+				g.structs[name] = true
+			}
+			for name, enum := range cr.Enums {
+				g.enums[name] = enum.Type
+			}
+		}
+
+		for _, cr := range res.Items {
+			code, err := g.generateApplyConfigCode(res, cr, typesImport)
+			if err != nil {
+				return nil, fmt.Errorf("error generating apply configuration content: %w", err)
+			}
+			outputFile := filepath.Join(pkgDir, strings.ToLower(cr.Kind)+".go")
+			files = append(files, File{
+				Name:       outputFile,
+				Content:    code,
+				template:   "applyconfiguration.go.tpl",
+				successMsg: "Successfully generated apply configurations",
+				successArgs: []any{
+					"group", res.Group, "version", res.Version, "kind", cr.Kind, "file", outputFile,
+				},
+			})
+		}
+
+		var sb strings.Builder
+		t := template.Must(template.New("applyconfiguration_schema.go.tpl").Parse(applyConfigSchemaTpl))
+		if err := t.Execute(&sb, map[string]any{
+			"AppName": myName,
+			"Version": res.Version,
+			"Schema":  g.schema(res),
+		}); err != nil {
+			return nil, fmt.Errorf("error generating apply configuration schema: %w", err)
+		}
+		outputFile := filepath.Join(pkgDir, "internal.go")
+		files = append(files, File{
+			Name:       outputFile,
+			Content:    sb.String(),
+			template:   "applyconfiguration_schema.go.tpl",
+			successMsg: "Successfully generated apply configuration schema",
+			successArgs: []any{
+				"group", res.Group, "version", res.Version, "file", outputFile,
+			},
+		})
+	}
+	return files, nil
+}
+
+func (g *applyConfigGenerator) generateApplyConfigCode(
+	res *openapi.CustomResources,
+	cr *openapi.CustomResource,
+	typesImport string,
+) (string, error) {
+	g.packages = packageSet{"metav1": true}
+
+	kind := applyConfigKind{
+		applyConfigStruct: g.newStruct(cr.Root, true),
+		Object:            paramName(cr.Kind),
+		APIVersion:        res.Group + "/" + res.Version,
+		Namespaced:        cr.Namespaced,
+	}
+	for _, sub := range cr.Subresources {
+		kind.Subresources = append(kind.Subresources, applyConfigSubresource{
+			Name: sub,
+			Func: "Extract" + cr.Kind + openapi.ToCamelCase(sub),
+		})
+	}
+	var structs []applyConfigStruct
+	for _, name := range slices.Sorted(maps.Keys(cr.Structs)) {
+		structs = append(structs, g.newStruct(cr.Structs[name], false))
+	}
+
+	imports := g.packages.imports(cr.Imports,
+		`"k8s.io/apimachinery/pkg/types"`,
+		`"k8s.io/apimachinery/pkg/util/managedfields"`,
+		`applymetav1 "k8s.io/client-go/applyconfigurations/meta/v1"`,
+		typesAlias+` "`+typesImport+`"`,
+	)
+
+	var sb strings.Builder
+	t := template.Must(template.New("applyconfiguration.go.tpl").Parse(applyConfigTpl))
+	err := t.Execute(&sb, map[string]any{
+		"AppName":    myName,
+		"Version":    res.Version,
+		"TypesAlias": typesAlias,
+		"Imports":    imports,
+		"Kind":       kind,
+		"Structs":    structs,
+	})
+	return sb.String(), err
+}
+
+func (g *applyConfigGenerator) newStruct(def *openapi.StructDef, root bool) applyConfigStruct {
+	s := applyConfigStruct{Name: def.Name, Description: def.Description}
+	for _, field := range def.Fields {
+		if root && isRootMetaField(field) {
+			continue
+		}
+		s.Fields = append(s.Fields, g.newField(field))
+	}
+	return s
+}
+
+// newField creates the apply configuration field of a struct field. All fields are optional,
+// scalars and external types are pointers, structs are replaced with their apply configuration.
+func (g *applyConfigGenerator) newField(field openapi.FieldDef) applyConfigField {
+	f := applyConfigField{Name: field.Name, JSONTag: field.JSONTag, Description: field.Description}
+	b := "b." + field.Name
+	t := strings.TrimPrefix(field.Type, "*")
+	switch {
+	case t == "any" || t == "[]byte":
+		// nil is the unset value of any and byte slices
+		f.Type, f.Param, f.Kind = t, "value "+t, "set"
+		f.Statements = []string{b + " = value"}
+	case g.structs[t]:
+		f.Type, f.Param, f.Kind = "*"+t+applyConfigSuffix, "value *"+t+applyConfigSuffix, "set"
+		f.Statements = []string{b + " = value"}
+	case strings.HasPrefix(t, "[]"):
+		elem := strings.TrimPrefix(t[2:], "*")
+		f.Type, f.Kind = "[]"+g.valueType(elem), "add"
+		if g.structs[elem] {
+			f.Param = "values ...*" + elem + applyConfigSuffix
+			f.Statements = block("for i := range values {",
+				append(block("if values[i] == nil {", `panic("nil value passed to With`+field.Name+`")`),
+					b+" = append("+b+", *values[i])")...)
+		} else {
+			f.Param = "values ..." + g.valueType(elem)
+			f.Statements = block("for i := range values {", b+" = append("+b+", values[i])")
+		}
+	case strings.HasPrefix(t, "map[string]"):
+		f.Type, f.Kind = "map[string]"+g.valueType(strings.TrimPrefix(t, "map[string]")), "put"
+		f.Param = "entries " + f.Type
+		f.Statements = append(
+			block("if "+b+" == nil && len(entries) > 0 {", b+" = make("+f.Type+", len(entries))"),
+			block("for k, v := range entries {", b+"[k] = v")...)
+	default:
+		t = g.goType(t)
+		f.Type, f.Param, f.Kind = "*"+t, "value "+t, "set"
+		f.Statements = []string{b + " = &value"}
+	}
+	return f
+}
+
+// valueType returns the type of a slice or map element in an apply configuration.
+func (g *applyConfigGenerator) valueType(t string) string {
+	t = strings.TrimPrefix(t, "*")
+	switch {
+	case g.structs[t]:
+		return t + applyConfigSuffix
+	case strings.HasPrefix(t, "[]"):
+		return "[]" + g.valueType(t[2:])
+	case strings.HasPrefix(t, "map[string]"):
+		return "map[string]" + g.valueType(strings.TrimPrefix(t, "map[string]"))
+	default:
+		return g.goType(t)
+	}
+}
+
+// goType returns the type expression of a scalar or external type, enum types are located in the types package.
+func (g *applyConfigGenerator) goType(t string) string {
+	if _, ok := g.enums[t]; ok {
+		return typesAlias + "." + t
+	}
+	return g.packages.typeExpr(t)
+}
+
+// schema creates the structured merge diff schema of the kinds and structs of the package.
+// Metadata and the types of other packages are deduced from their values.
+func (g *applyConfigGenerator) schema(res *openapi.CustomResources) string {
+	defs := make(map[string]*openapi.StructDef)
+	for _, cr := range res.Items {
+		defs[cr.Kind] = cr.Root
+		maps.Copy(defs, cr.Structs)
+	}
+
+	var lines []string
+	for _, name := range slices.Sorted(maps.Keys(defs)) {
+		lines = append(lines, "- name: "+name, "  map:", "    fields:")
+		def := defs[name]
+		if def.Root {
+			lines = append(lines,
+				"    - name: apiVersion", "      type:", "        scalar: string",
+				"    - name: kind", "      type:", "        scalar: string",
+				"    - name: metadata", "      type:", "        namedType: "+untypedDeduced, "      default: {}",
+			)
+		}
+		for _, field := range def.Fields {
+			if def.Root && isRootMetaField(field) {
+				continue
+			}
+			lines = append(lines, "    - name: "+field.JSONTag, "      type:")
+			lines = append(lines, indent("        ", g.typeSchema(field.Type, field.Markers))...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// typeSchema creates the schema of a go type. The list and map types are defined by the markers of the field.
+func (g *applyConfigGenerator) typeSchema(t string, markers []string) []string {
+	t = strings.TrimPrefix(t, "*")
+	switch {
+	case g.structs[t]:
+		return []string{"namedType: " + t}
+	case t == "[]byte":
+		// byte slices are base64 encoded strings
+		return []string{"scalar: string"}
+	case strings.HasPrefix(t, "[]"):
+		lines := append([]string{"list:", "  elementType:"}, indent("    ", g.typeSchema(t[2:], nil))...)
+		switch markerValue(markers, "listType=") {
+		case "map":
+			lines = append(lines, "  elementRelationship: associative", "  keys:")
+			for _, m := range markers {
+				if key, ok := strings.CutPrefix(m, "listMapKey="); ok {
+					lines = append(lines, "  - "+key)
+				}
+			}
+		case "set":
+			lines = append(lines, "  elementRelationship: associative")
+		default:
+			lines = append(lines, "  elementRelationship: atomic")
+		}
+		return lines
+	case strings.HasPrefix(t, "map[string]"):
+		lines := []string{"map:", "  elementType:"}
+		lines = append(lines, indent("    ", g.typeSchema(strings.TrimPrefix(t, "map[string]"), nil))...)
+		if markerValue(markers, "mapType=") == "atomic" {
+			lines = append(lines, "  elementRelationship: atomic")
+		}
+		return lines
+	}
+
+	if base, ok := g.enums[t]; ok {
+		t = base
+	}
+	switch t {
+	case "string":
+		return []string{"scalar: string"}
+	case "bool":
+		return []string{"scalar: boolean"}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return []string{"scalar: numeric"}
+	default:
+		return []string{"namedType: " + untypedDeduced}
+	}
+}
+
+// paramName returns the lower case kind as parameter name, suffixed if it is a go keyword.
+func paramName(kind string) string {
+	name := strings.ToLower(kind)
+	if token.IsKeyword(name) {
+		return name + "Obj"
+	}
+	return name
+}
+
+// markerValue returns the value of the first marker with the prefix.
+func markerValue(markers []string, prefix string) string {
+	for _, m := range markers {
+		if v, ok := strings.CutPrefix(m, prefix); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// indent prefixes the lines with the indentation.
+func indent(prefix string, lines []string) []string {
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+	return lines
+}
@@ -0,0 +1,139 @@
+package render
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+// celRule is a x-kubernetes-validations rule with the JSON path segments of the values it applies to.
+type celRule struct {
+	openapi.ValidationRule
+	Path []string
+}
+
+// PathString returns the JSON path of the values the rule applies to, self for the object.
+func (r celRule) PathString() string {
+	if len(r.Path) == 0 {
+		return "self"
+	}
+	var sb strings.Builder
+	for i, segment := range r.Path {
+		if i > 0 && segment != "[*]" && segment != "{*}" {
+			sb.WriteString(".")
+		}
+		sb.WriteString(segment)
+	}
+	return sb.String()
+}
+
+// celKind holds all CEL rules of a kind.
+type celKind struct {
+	Name  string
+	Rules []celRule
+	// Skipped are the rules that do not compile in the environment of the generated code.
+	Skipped []celRule
+}
+
+// celEnv returns the CEL environment of the generated validateCEL function. Transition rules are compiled
+// with oldSelf, they are skipped when evaluating.
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("self", cel.DynType),
+		cel.Variable("oldSelf", cel.DynType),
+		cel.OptionalTypes(),
+		cel.CrossTypeNumericComparisons(true),
+		ext.Strings(),
+		ext.Sets(),
+		ext.Lists(),
+	)
+})
+
+// compiles checks if the rule compiles in the environment of the generated code.
+// Rules using functions of the Kubernetes CEL libraries, like url() or quantity(), do not compile.
+func (r celRule) compiles() (bool, error) {
+	env, err := celEnv()
+	if err != nil {
+		return false, err
+	}
+	_, iss := env.Compile(r.Rule)
+	return iss.Err() == nil, nil
+}
+
+// generateCELCode generates the ValidateCEL methods of all kinds.
+func generateCELCode(resources *openapi.CustomResources, pkgName string) (string, error) {
+	structs := make(map[string]*openapi.StructDef)
+	for _, cr := range resources.Items {
+		maps.Copy(structs, cr.Structs)
+	}
+
+	var kinds []celKind
+	for _, cr := range resources.Items {
+		var rules []celRule
+		for _, rule := range cr.Root.Validations {
+			rules = append(rules, celRule{ValidationRule: rule})
+		}
+		rules = append(rules, collectCELRules(cr.Root, structs, nil)...)
+
+		kind := celKind{Name: cr.Kind}
+		for _, rule := range rules {
+			ok, err := rule.compiles()
+			if err != nil {
+				return "", err
+			}
+			if ok {
+				kind.Rules = append(kind.Rules, rule)
+			} else {
+				kind.Skipped = append(kind.Skipped, rule)
+			}
+		}
+		kinds = append(kinds, kind)
+	}
+
+	var sb strings.Builder
+	t := template.Must(template.New("cel.go.tpl").Parse(celTpl))
+	err := t.Execute(&sb, map[string]any{
+		"AppName": myName,
+		"Version": resources.Version,
+		"Package": pkgName,
+		"Kinds":   kinds,
+	})
+	return sb.String(), err
+}
+
+// collectCELRules collects the rules of all fields of a struct and its nested structs.
+func collectCELRules(def *openapi.StructDef, structs map[string]*openapi.StructDef, path []string) []celRule {
+	var rules []celRule
+	for _, field := range def.Fields {
+		fieldPath := append(slices.Clone(path), field.JSONTag)
+		for _, rule := range field.Validations {
+			rulePath := fieldPath
+			if rule.Scope != "" {
+				rulePath = append(slices.Clone(fieldPath), rule.Scope)
+			}
+			rules = append(rules, celRule{ValidationRule: rule, Path: rulePath})
+		}
+
+		ref := parseTypeRef(field.Type)
+		child, ok := structs[ref.Name]
+		if !ok {
+			continue
+		}
+		switch {
+		case ref.Slice:
+			fieldPath = append(fieldPath, "[*]")
+		case ref.Map:
+			fieldPath = append(fieldPath, "{*}")
+		default:
+		}
+		rules = append(rules, collectCELRules(child, structs, fieldPath)...)
+	}
+	return rules
+}
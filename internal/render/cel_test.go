@@ -0,0 +1,29 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+func Test_celRule_compiles(t *testing.T) {
+	for rule, expected := range map[string]bool{
+		"self.minReplicas <= self.maxReplicas":             true,
+		"self == oldSelf":                                  true,
+		"self.name.lowerAscii().startsWith('a')":           true,
+		"isURL(self)":                                      false,
+		"quantity(self.size).isGreaterThan(quantity('1'))": false,
+	} {
+		ok, err := celRule{ValidationRule: openapi.ValidationRule{Rule: rule}}.compiles()
+		require.NoError(t, err)
+		assert.Equal(t, expected, ok, rule)
+	}
+}
+
+func Test_celRule_PathString(t *testing.T) {
+	assert.Equal(t, "self", celRule{}.PathString())
+	assert.Equal(t, "spec.listeners[*].port", celRule{Path: []string{"spec", "listeners", "[*]", "port"}}.PathString())
+}
@@ -0,0 +1,189 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+const devNull = "/dev/null"
+
+// ErrStaleFiles is returned if the generated files on disk differ from the rendered files.
+var ErrStaleFiles = errors.New("generated files are not up to date")
+
+// CheckCrdFiles renders the files of all packages in memory and compares them with the files in the target
+// directory, without writing. The unified diff of the differences is written to w.
+func CheckCrdFiles(
+	ctx context.Context,
+	packages []*openapi.CustomResources,
+	targetDir string,
+	opts Options,
+	w io.Writer,
+) error {
+	files, err := Render(packages, targetDir, opts)
+	if err != nil {
+		return err
+	}
+	if err := CheckFiles(files, targetDir, w); err != nil {
+		return err
+	}
+	slog.With("target", targetDir, "files", len(files)).InfoContext(ctx, "Generated files are up to date")
+	return nil
+}
+
+// CheckFiles compares the rendered files byte for byte with the files on disk. Files generated by crd-gen in the
+// rendered package directories that are not rendered anymore are reported as stale. The unified diff of the
+// differences is written to w, an error wrapping ErrStaleFiles is returned if any file differs.
+// An empty target directory is the current directory.
+func CheckFiles(files []File, targetDir string, w io.Writer) error {
+	if targetDir == "" {
+		targetDir = "."
+	}
+	sorted := slices.SortedFunc(slices.Values(files), func(a, b File) int { return strings.Compare(a.Name, b.Name) })
+	rendered := make(map[string]bool)
+	var diffs []string
+	for _, f := range sorted {
+		rendered[filepath.Clean(f.Name)] = true
+
+		fromFile := f.Name
+		current, err := os.ReadFile(f.Name)
+		if errors.Is(err, fs.ErrNotExist) {
+			fromFile = devNull
+		} else if err != nil {
+			return fmt.Errorf("error reading generated file: %w", err)
+		}
+		if string(current) == f.Content {
+			continue
+		}
+		diff, err := unifiedDiff(string(current), f.Content, fromFile, f.Name)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, f.Name)
+		if _, err := io.WriteString(w, diff); err != nil {
+			return err
+		}
+	}
+
+	stale, err := staleFiles(targetDir, rendered)
+	if err != nil {
+		return err
+	}
+	for _, name := range stale {
+		current, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("error reading generated file: %w", err)
+		}
+		diff, err := unifiedDiff(string(current), "", name, devNull)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, name)
+		if _, err := io.WriteString(w, diff); err != nil {
+			return err
+		}
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("%w: %d files differ: %s", ErrStaleFiles, len(diffs), strings.Join(diffs, ", "))
+	}
+	return nil
+}
+
+// unifiedDiff returns the unified diff between the content on disk and the rendered content.
+func unifiedDiff(current, rendered, fromFile, toFile string) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(current),
+		B:        splitLines(rendered),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating diff of %s: %w", toFile, err)
+	}
+	return diff, nil
+}
+
+// splitLines splits the content into lines, keeping the line endings.
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// staleFiles returns the go files generated by crd-gen, which are not rendered anymore. Only the directories of the
+// rendered files in the target directory are read, as other directories may belong to other runs or generators.
+func staleFiles(targetDir string, rendered map[string]bool) ([]string, error) {
+	dirs := make(map[string]bool)
+	for name := range rendered {
+		dir := filepath.Dir(name)
+		if rel, err := filepath.Rel(targetDir, dir); err == nil && filepath.IsLocal(rel) {
+			dirs[dir] = true
+		}
+	}
+
+	var stale []string
+	for _, dir := range slices.Sorted(maps.Keys(dirs)) {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading target directory: %w", err)
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			if e.IsDir() || filepath.Ext(path) != ".go" || rendered[path] {
+				continue
+			}
+			generated, err := isGeneratedFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if generated {
+				stale = append(stale, path)
+			}
+		}
+	}
+	return stale, nil
+}
+
+// isGeneratedFile checks if the go file has a generated code comment of crd-gen.
+// Files of other generators and files that can not be parsed are not considered generated.
+func isGeneratedFile(path string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), path, src, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil || !ast.IsGenerated(file) {
+		return false, nil //nolint:nilerr // files that are no valid go are not generated by crd-gen
+	}
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		if strings.Contains(group.Text(), "Code generated by "+myName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,99 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFiles(t *testing.T) {
+	dir := t.TempDir()
+	upToDate := File{Name: filepath.Join(dir, "v1", "types_a.go"), Content: "package v1\n\ntype A struct{}\n"}
+	modified := File{Name: filepath.Join(dir, "v1", "types_b.go"), Content: "package v1\n\ntype B struct{}\n"}
+	missing := File{Name: filepath.Join(dir, "v1", "types_c.go"), Content: "package v1\n"}
+	stale := filepath.Join(dir, "v1", "types_old.go")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "v1"), 0o755))
+	require.NoError(t, os.WriteFile(upToDate.Name, []byte(upToDate.Content), 0o600))
+	require.NoError(t, os.WriteFile(modified.Name, []byte("package v1\n\ntype B int\n"), 0o600))
+	require.NoError(t, os.WriteFile(stale, []byte("// Code generated by crd-gen. DO NOT EDIT.\n\npackage v1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "v1", "doc.go"), []byte("package v1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "v1", "zz_generated.other.go"),
+		[]byte("// Code generated by other-gen. DO NOT EDIT.\n\npackage v1\n"), 0o600))
+
+	w := new(bytes.Buffer)
+	err := CheckFiles([]File{missing, modified, upToDate}, dir, w)
+	require.ErrorIs(t, err, ErrStaleFiles)
+	assert.Contains(t, err.Error(), "3 files differ")
+
+	assert.Equal(t, `--- `+modified.Name+`
++++ `+modified.Name+`
+@@ -1,3 +1,3 @@
+ package v1
+ 
+-type B int
++type B struct{}
+--- /dev/null
++++ `+missing.Name+`
+@@ -0,0 +1 @@
++package v1
+--- `+stale+`
++++ /dev/null
+@@ -1,3 +0,0 @@
+-// Code generated by crd-gen. DO NOT EDIT.
+-
+-package v1
+`, w.String())
+
+	_, err = os.Stat(missing.Name)
+	require.ErrorIs(t, err, os.ErrNotExist, "check must not write files")
+}
+
+func TestCheckFiles_upToDate(t *testing.T) {
+	dir := t.TempDir()
+	f := File{Name: filepath.Join(dir, "types_a.go"), Content: "package v1\n"}
+	require.NoError(t, os.WriteFile(f.Name, []byte(f.Content), 0o600))
+
+	w := new(bytes.Buffer)
+	require.NoError(t, CheckFiles([]File{f}, dir, w))
+	assert.Empty(t, w.String())
+}
+
+func TestCheckFiles_missingTarget(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "apis")
+	f := File{Name: filepath.Join(dir, "types_a.go"), Content: "package v1\n"}
+
+	err := CheckFiles([]File{f}, dir, new(bytes.Buffer))
+	require.ErrorIs(t, err, ErrStaleFiles)
+}
+
+func TestCheckFiles_siblingPackage(t *testing.T) {
+	dir := t.TempDir()
+	f := File{Name: filepath.Join(dir, "v1", "types_a.go"), Content: "package v1\n"}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "v1"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "v2"), 0o755))
+	require.NoError(t, os.WriteFile(f.Name, []byte(f.Content), 0o600))
+	// generated by another run into a sibling directory
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "v2", "zz_generated.deepcopy.go"),
+		[]byte("// Code generated by crd-gen. DO NOT EDIT.\n\npackage v2\n"), 0o600))
+
+	w := new(bytes.Buffer)
+	require.NoError(t, CheckFiles([]File{f}, dir, w))
+	assert.Empty(t, w.String())
+}
+
+func TestCheckFiles_emptyTarget(t *testing.T) {
+	t.Chdir(t.TempDir())
+	f := File{Name: "types_a.go", Content: "package v1\n"}
+	require.NoError(t, os.WriteFile(f.Name, []byte(f.Content), 0o600))
+	require.NoError(t, os.WriteFile("types_old.go",
+		[]byte("// Code generated by crd-gen. DO NOT EDIT.\n\npackage v1\n"), 0o600))
+
+	err := CheckFiles([]File{f}, "", new(bytes.Buffer))
+	require.ErrorIs(t, err, ErrStaleFiles)
+	assert.Contains(t, err.Error(), "types_old.go")
+}
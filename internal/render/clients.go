@@ -0,0 +1,296 @@
+package render
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+const (
+	clientsetDir = "clientset/versioned"
+	listersDir   = "listers"
+	informersDir = "informers/externalversions"
+)
+
+// clientMessages are the log messages of the generated client files by template name.
+var clientMessages = map[string]string{
+	"clientset":          "Successfully generated clientset",
+	"scheme":             "Successfully generated clientset scheme",
+	"client":             "Successfully generated group client",
+	"group":              "Successfully generated group informers",
+	"type":               "Successfully generated typed client",
+	"lister":             "Successfully generated lister",
+	"factory":            "Successfully generated informer factory",
+	"internalinterfaces": "Successfully generated informer interfaces",
+	"version":            "Successfully generated version informers",
+	"informer":           "Successfully generated informer",
+}
+
+// clientGroup is an API group of the clientset and informers.
+type clientGroup struct {
+	Group string
+	// Package is the go package name of the group.
+	Package string
+	// GoName is the name of the group in go identifiers.
+	GoName          string
+	InformersImport string
+	Versions        []*clientGroupVersion
+}
+
+// clientGroupVersion is a group version package of the clientset, listers and informers.
+type clientGroupVersion struct {
+	Group   string
+	Version string
+	Package string
+	// Alias is the import alias of the packages of the group version.
+	Alias string
+	// GoName is the name of the group version in go identifiers, e.g. AppsV1.
+	GoName string
+	// VersionName is the name of the version in go identifiers, e.g. V1.
+	VersionName     string
+	TypesImport     string
+	TypedImport     string
+	ListersImport   string
+	InformersImport string
+	// ApplyImport is the import path of the apply configurations, empty if they are not generated.
+	ApplyImport string
+	Kinds       []clientKind
+}
+
+// clientKind is a kind of the clientset, listers and informers.
+type clientKind struct {
+	Kind string
+	List string
+	// Resource is the plural resource name of the kind.
+	Resource string
+	// Plural is the plural name of the kind in go identifiers.
+	Plural string
+	// Object is the parameter name of an object of the kind.
+	Object string
+	// Private is the kind with a lower case first letter.
+	Private string
+	// PrivatePlural is the plural with a lower case first letter.
+	PrivatePlural string
+	Namespaced    bool
+	Status        bool
+}
+
+// renderClients generates the typed clientset, the listers and the informers of all packages.
+func renderClients(
+	packages []*openapi.CustomResources,
+	pkgDirs map[*openapi.CustomResources]string,
+	targetDir string,
+	opts Options,
+) ([]File, error) {
+	importPath := func(dir ...string) (string, error) {
+		return packageImportPath(targetDir, filepath.Join(append([]string{targetDir}, dir...)...), opts.ImportPath)
+	}
+
+	groupPackages := clientGroupPackages(packages)
+	groups := make(map[string]*clientGroup)
+	var groupVersions []*clientGroupVersion
+	for _, res := range packages {
+		pkg := groupPackages[res.Group]
+		g, ok := groups[res.Group]
+		if !ok {
+			informersImport, err := importPath(informersDir, pkg)
+			if err != nil {
+				return nil, fmt.Errorf("error evaluating import path of the informers: %w", err)
+			}
+			g = &clientGroup{
+				Group:           res.Group,
+				Package:         pkg,
+				GoName:          openapi.ToCamelCase(pkg),
+				InformersImport: informersImport,
+			}
+			groups[res.Group] = g
+		}
+
+		gv := &clientGroupVersion{
+			Group:       res.Group,
+			Version:     res.Version,
+			Package:     pkg,
+			Alias:       pkg + res.Version,
+			GoName:      g.GoName + openapi.ToCamelCase(res.Version),
+			VersionName: openapi.ToCamelCase(res.Version),
+		}
+		var err error
+		if gv.TypesImport, err = packageImportPath(targetDir, pkgDirs[res], opts.ImportPath); err != nil {
+			return nil, fmt.Errorf("error evaluating import path of version %q: %w", res.Version, err)
+		}
+		rel, err := filepath.Rel(targetDir, pkgDirs[res])
+		if err != nil {
+			return nil, err
+		}
+		for target, dir := range map[*string][]string{
+			&gv.TypedImport:     {clientsetDir, "typed", pkg, res.Version},
+			&gv.ListersImport:   {listersDir, pkg, res.Version},
+			&gv.InformersImport: {informersDir, pkg, res.Version},
+		} {
+			if *target, err = importPath(dir...); err != nil {
+				return nil, fmt.Errorf("error evaluating import path of the clients: %w", err)
+			}
+		}
+		if opts.ApplyConfigurations {
+			if gv.ApplyImport, err = importPath(applyConfigDir, rel); err != nil {
+				return nil, fmt.Errorf("error evaluating import path of the apply configurations: %w", err)
+			}
+		}
+		for _, cr := range res.Items {
+			plural := pluralName(cr.Kind, cr.Plural)
+			gv.Kinds = append(gv.Kinds, clientKind{
+				Kind:          cr.Kind,
+				List:          cr.List,
+				Resource:      cr.Plural,
+				Plural:        plural,
+				Object:        paramName(cr.Kind),
+				Private:       lowerFirst(cr.Kind),
+				PrivatePlural: lowerFirst(plural),
+				Namespaced:    cr.Namespaced,
+				Status:        slices.Contains(cr.Subresources, "status"),
+			})
+		}
+		g.Versions = append(g.Versions, gv)
+		groupVersions = append(groupVersions, gv)
+	}
+
+	clientsetImport, err := importPath(clientsetDir)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating import path of the clientset: %w", err)
+	}
+	internalImport, err := importPath(informersDir, "internalinterfaces")
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating import path of the informers: %w", err)
+	}
+	sortedGroups := slices.SortedFunc(maps.Values(groups), func(a, b *clientGroup) int {
+		return strings.Compare(a.Package, b.Package)
+	})
+	data := map[string]any{
+		"AppName":         myName,
+		"ClientsetImport": clientsetImport,
+		"SchemeImport":    clientsetImport + "/scheme",
+		"InternalImport":  internalImport,
+		"GroupVersions":   groupVersions,
+		"Groups":          sortedGroups,
+	}
+
+	var files []File
+	add := func(tpl *template.Template, name string, data any, dir ...string) error {
+		var sb strings.Builder
+		if err := tpl.ExecuteTemplate(&sb, name, data); err != nil {
+			return fmt.Errorf("error generating %s content: %w", name, err)
+		}
+		outputFile := filepath.Join(append([]string{targetDir}, dir...)...)
+		files = append(files, File{
+			Name:        outputFile,
+			Content:     sb.String(),
+			template:    tpl.Name() + ":" + name,
+			successMsg:  clientMessages[name],
+			successArgs: []any{"file", outputFile},
+		})
+		return nil
+	}
+
+	clientsetTpl := template.Must(template.New("clientset.go.tpl").Parse(clientsetTpl))
+	listersTpl := template.Must(template.New("listers.go.tpl").Parse(listersTpl))
+	informersTpl := template.Must(template.New("informers.go.tpl").Parse(informersTpl))
+
+	if err := add(clientsetTpl, "clientset", data, clientsetDir, "clientset.go"); err != nil {
+		return nil, err
+	}
+	if err := add(clientsetTpl, "scheme", data, clientsetDir, "scheme", "register.go"); err != nil {
+		return nil, err
+	}
+	if err := add(informersTpl, "factory", data, informersDir, "factory.go"); err != nil {
+		return nil, err
+	}
+	if err := add(informersTpl, "internalinterfaces", data, informersDir, "internalinterfaces",
+		"factory_interfaces.go"); err != nil {
+		return nil, err
+	}
+	for _, g := range sortedGroups {
+		groupData := map[string]any{"AppName": myName, "InternalImport": internalImport, "Group": g}
+		if err := add(informersTpl, "group", groupData, informersDir, g.Package, "interface.go"); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, gv := range groupVersions {
+		gvData := map[string]any{
+			"AppName":         myName,
+			"ClientsetImport": clientsetImport,
+			"SchemeImport":    clientsetImport + "/scheme",
+			"InternalImport":  internalImport,
+			"GroupVersion":    gv,
+		}
+		typedDir := []string{clientsetDir, "typed", gv.Package, gv.Version}
+		if err := add(clientsetTpl, "client", gvData, append(typedDir, gv.Package+"_client.go")...); err != nil {
+			return nil, err
+		}
+		if err := add(informersTpl, "version", gvData, informersDir, gv.Package, gv.Version, "interface.go"); err != nil {
+			return nil, err
+		}
+		for _, kind := range gv.Kinds {
+			gvData["Kind"] = kind
+			fileName := strings.ToLower(kind.Kind) + ".go"
+			if err := add(clientsetTpl, "type", gvData, append(typedDir, fileName)...); err != nil {
+				return nil, err
+			}
+			if err := add(listersTpl, "lister", gvData, listersDir, gv.Package, gv.Version, fileName); err != nil {
+				return nil, err
+			}
+			if err := add(informersTpl, "informer", gvData, informersDir, gv.Package, gv.Version, fileName); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// clientGroupPackages evaluates the go package names of the groups in the clientset and informers.
+// The package name is the short name of the group, or the full group name if the short name is not unique.
+func clientGroupPackages(packages []*openapi.CustomResources) map[string]string {
+	groups := make(map[string]bool)
+	shortNames := make(map[string]int)
+	for _, res := range packages {
+		if !groups[res.Group] {
+			groups[res.Group] = true
+			shortNames[groupShortName(res.Group)]++
+		}
+	}
+	names := make(map[string]string)
+	for group := range groups {
+		names[group] = groupShortName(group)
+		if shortNames[names[group]] > 1 {
+			names[group] = strings.Map(func(r rune) rune {
+				if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+					return r
+				}
+				return -1
+			}, strings.ToLower(group))
+		}
+	}
+	return names
+}
+
+// pluralName returns the plural of the kind in go identifiers.
+// If the resource name starts with the kind, the kind keeps its case, e.g. AllCases for allcases.
+func pluralName(kind, resource string) string {
+	if suffix, ok := strings.CutPrefix(resource, strings.ToLower(kind)); ok {
+		return kind + suffix
+	}
+	return openapi.ToCamelCase(resource)
+}
+
+// lowerFirst returns the name with a lower case first letter.
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
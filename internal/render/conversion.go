@@ -0,0 +1,331 @@
+package render
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+const hubAlias = "hub"
+
+// conversionPackage is a group version package with its structs.
+type conversionPackage struct {
+	resources *openapi.CustomResources
+	structs   map[string]*openapi.StructDef
+	kinds     map[string]*openapi.CustomResource
+}
+
+// convertFunc converts a struct of one version into the struct of another version.
+type convertFunc struct {
+	Name       string
+	In         string
+	Out        string
+	Statements []string
+}
+
+// convertibleKind is a spoke kind implementing conversion.Convertible.
+type convertibleKind struct {
+	Name     string
+	HubName  string
+	ToHub    string
+	FromHub  string
+	Complete bool
+}
+
+// conversionGenerator generates the conversion functions between a spoke and a hub package.
+type conversionGenerator struct {
+	spoke, hub *conversionPackage
+	funcs      map[string]*convertFunc
+	queue      []*convertFunc
+	complete   bool
+}
+
+// renderConversions generates the conversion files of all packages.
+// The hub of a kind is the package of the hub version, or the storage version if no hub version is defined.
+func renderConversions(
+	packages []*openapi.CustomResources,
+	pkgDirs map[*openapi.CustomResources]string,
+	targetDir string,
+	opts Options,
+) ([]File, error) {
+	convPackages := make(map[*openapi.CustomResources]*conversionPackage)
+	for _, res := range packages {
+		cp := &conversionPackage{
+			resources: res,
+			structs:   make(map[string]*openapi.StructDef),
+			kinds:     make(map[string]*openapi.CustomResource),
+		}
+		for _, cr := range res.Items {
+			maps.Copy(cp.structs, cr.Structs)
+			cp.kinds[cr.Kind] = cr
+		}
+		convPackages[res] = cp
+	}
+
+	var files []File
+	for _, res := range packages {
+		var hubKinds []string
+		var spokeKinds []convertibleKind
+		var funcs []*convertFunc
+		hubImport := ""
+
+		for _, cr := range res.Items {
+			hub := findHub(packages, res.Group, cr.Kind, opts.HubVersion)
+			switch {
+			case hub == nil:
+				continue
+			case hub == res:
+				hubKinds = append(hubKinds, cr.Kind)
+				continue
+			case hubImport == "":
+				importPath, err := packageImportPath(targetDir, pkgDirs[hub], opts.ImportPath)
+				if err != nil {
+					return nil, fmt.Errorf("error evaluating import path of hub version %q: %w", hub.Version, err)
+				}
+				hubImport = importPath
+			default:
+			}
+
+			g := &conversionGenerator{
+				spoke:    convPackages[res],
+				hub:      convPackages[hub],
+				funcs:    make(map[string]*convertFunc),
+				complete: true,
+			}
+			spokeKinds = append(spokeKinds, convertibleKind{
+				Name:    cr.Kind,
+				HubName: hubAlias + "." + cr.Kind,
+				ToHub:   g.convertFuncName(cr.Kind, cr.Kind, true),
+				FromHub: g.convertFuncName(cr.Kind, cr.Kind, false),
+			})
+			g.run(cr.Root, g.hub.kinds[cr.Kind].Root)
+			spokeKinds[len(spokeKinds)-1].Complete = g.complete
+			for _, name := range slices.Sorted(maps.Keys(g.funcs)) {
+				funcs = append(funcs, g.funcs[name])
+			}
+		}
+
+		if len(hubKinds) == 0 && len(spokeKinds) == 0 {
+			continue
+		}
+
+		var sb strings.Builder
+		t := template.Must(template.New("conversion.go.tpl").Parse(conversionTpl))
+		if err := t.Execute(&sb, map[string]any{
+			"AppName":   myName,
+			"Version":   res.Version,
+			"Package":   packageName(res, opts),
+			"HubAlias":  hubAlias,
+			"HubImport": hubImport,
+			"HubKinds":  hubKinds,
+			"Kinds":     spokeKinds,
+			"Funcs":     funcs,
+		}); err != nil {
+			return nil, fmt.Errorf("error generating conversion content: %w", err)
+		}
+
+		outputFile := filepath.Join(pkgDirs[res], "zz_generated.conversion.go")
+		files = append(files, File{
+			Name:       outputFile,
+			Content:    sb.String(),
+			template:   "conversion.go.tpl",
+			successMsg: "Successfully generated conversions",
+			successArgs: []any{
+				"group", res.Group, "version", res.Version, "file", outputFile,
+			},
+		})
+	}
+	return files, nil
+}
+
+func findHub(packages []*openapi.CustomResources, group, kind, hubVersion string) *openapi.CustomResources {
+	for _, res := range packages {
+		if res.Group != group {
+			continue
+		}
+		for _, cr := range res.Items {
+			if cr.Kind != kind {
+				continue
+			}
+			if (hubVersion == "" && cr.Storage) || hubVersion == res.Version {
+				return res
+			}
+		}
+	}
+	return nil
+}
+
+func (g *conversionGenerator) convertFuncName(spokeName, hubName string, toHub bool) string {
+	if toHub {
+		return fmt.Sprintf("convert_%s_%s_To_%s_%s", g.spoke.resources.Version, spokeName, g.hub.resources.Version, hubName)
+	}
+	return fmt.Sprintf("convert_%s_%s_To_%s_%s", g.hub.resources.Version, hubName, g.spoke.resources.Version, spokeName)
+}
+
+// run generates the conversion functions of the root structs and all nested structs in both directions.
+func (g *conversionGenerator) run(spokeRoot, hubRoot *openapi.StructDef) {
+	g.convertStructs(spokeRoot, hubRoot)
+	for len(g.queue) > 0 {
+		f := g.queue[0]
+		g.queue = g.queue[1:]
+		g.fillFunc(f)
+	}
+}
+
+// convertStructs registers the conversion functions of a struct pair and returns the names.
+func (g *conversionGenerator) convertStructs(spokeDef, hubDef *openapi.StructDef) (toHub, fromHub string) {
+	toHub = g.convertFuncName(spokeDef.Name, hubDef.Name, true)
+	fromHub = g.convertFuncName(spokeDef.Name, hubDef.Name, false)
+	if _, ok := g.funcs[toHub]; !ok {
+		to := &convertFunc{Name: toHub, In: spokeDef.Name, Out: hubAlias + "." + hubDef.Name}
+		from := &convertFunc{Name: fromHub, In: hubAlias + "." + hubDef.Name, Out: spokeDef.Name}
+		g.funcs[toHub] = to
+		g.funcs[fromHub] = from
+		g.queue = append(g.queue, to, from)
+	}
+	return toHub, fromHub
+}
+
+func (g *conversionGenerator) fillFunc(f *convertFunc) {
+	toHub := !strings.HasPrefix(f.In, hubAlias+".")
+	inDef, outDef := g.spoke.structs[f.In], g.hub.structs[strings.TrimPrefix(f.Out, hubAlias+".")]
+	inVersion, outVersion := g.spoke.resources.Version, g.hub.resources.Version
+	if !toHub {
+		inDef, outDef = g.hub.structs[strings.TrimPrefix(f.In, hubAlias+".")], g.spoke.structs[f.Out]
+		inVersion, outVersion = outVersion, inVersion
+	}
+	if inDef == nil {
+		inDef, outDef = g.rootStructs(f, toHub)
+	}
+
+	outFields := make(map[string]openapi.FieldDef)
+	for _, field := range outDef.Fields {
+		outFields[field.JSONTag] = field
+	}
+
+	for _, in := range inDef.Fields {
+		out, ok := outFields[in.JSONTag]
+		if !ok {
+			g.complete = false
+			f.Statements = append(f.Statements,
+				fmt.Sprintf("// TODO: in.%s has no counterpart in %s and is not converted", in.Name, outVersion))
+			continue
+		}
+		delete(outFields, in.JSONTag)
+		f.Statements = append(f.Statements, g.convertField(in, out, toHub)...)
+	}
+
+	for _, jsonTag := range slices.Sorted(maps.Keys(outFields)) {
+		g.complete = false
+		f.Statements = append(f.Statements,
+			fmt.Sprintf("// TODO: out.%s has no counterpart in %s and is not set", outFields[jsonTag].Name, inVersion))
+	}
+}
+
+// rootStructs returns the root struct definitions of a kind conversion.
+func (g *conversionGenerator) rootStructs(f *convertFunc, toHub bool) (inDef, outDef *openapi.StructDef) {
+	spokeName, hubName := f.In, strings.TrimPrefix(f.Out, hubAlias+".")
+	if !toHub {
+		spokeName, hubName = f.Out, strings.TrimPrefix(f.In, hubAlias+".")
+	}
+	spokeDef, hubDef := g.spoke.kinds[spokeName].Root, g.hub.kinds[hubName].Root
+	if toHub {
+		return spokeDef, hubDef
+	}
+	return hubDef, spokeDef
+}
+
+// convertField creates the statements converting a field.
+func (g *conversionGenerator) convertField(in, out openapi.FieldDef, toHub bool) []string {
+	inRef, outRef := parseTypeRef(in.Type), parseTypeRef(out.Type)
+	inStructs, outStructs := g.spoke.structs, g.hub.structs
+	outPrefix := hubAlias + "."
+	if !toHub {
+		inStructs, outStructs = outStructs, inStructs
+		outPrefix = ""
+	}
+
+	incompatible := []string{
+		fmt.Sprintf("// TODO: in.%s (%s) can not be converted to out.%s (%s)", in.Name, in.Type, out.Name, out.Type),
+	}
+	if inRef.Pointer != outRef.Pointer || inRef.Slice != outRef.Slice || inRef.Map != outRef.Map ||
+		inRef.ElemPointer != outRef.ElemPointer {
+		g.complete = false
+		return incompatible
+	}
+
+	inStruct, outStruct := inStructs[inRef.Name], outStructs[outRef.Name]
+	switch {
+	case !inRef.isLocal() && !outRef.isLocal():
+		if in.Type != out.Type {
+			g.complete = false
+			return incompatible
+		}
+		return []string{fmt.Sprintf("out.%s = in.%s", out.Name, in.Name)}
+	case inStruct != nil && outStruct != nil:
+		var call string
+		if toHub {
+			call, _ = g.convertStructs(inStruct, outStruct)
+		} else {
+			_, call = g.convertStructs(outStruct, inStruct)
+		}
+		return []string{convertStructStatement(in.Name, out.Name, outPrefix+outRef.Name, call, inRef)}
+	case inStruct == nil && outStruct == nil && inRef.isLocal() && outRef.isLocal():
+		// enum types
+		return []string{convertValueStatement(in.Name, out.Name, outPrefix+outRef.Name, inRef)}
+	default:
+		g.complete = false
+		return incompatible
+	}
+}
+
+func convertStructStatement(in, out, outType, call string, ref typeRef) string {
+	switch {
+	case ref.Pointer:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = new(%[3]s)\n\t\t%[4]s(in.%[1]s, out.%[2]s)\n\t}",
+			in, out, outType, call)
+	case ref.Slice && ref.ElemPointer:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = make([]*%[3]s, len(in.%[1]s))\n"+
+			"\t\tfor i := range in.%[1]s {\n\t\t\tif in.%[1]s[i] != nil {\n\t\t\t\tout.%[2]s[i] = new(%[3]s)\n"+
+			"\t\t\t\t%[4]s(in.%[1]s[i], out.%[2]s[i])\n\t\t\t}\n\t\t}\n\t}", in, out, outType, call)
+	case ref.Slice:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = make([]%[3]s, len(in.%[1]s))\n"+
+			"\t\tfor i := range in.%[1]s {\n\t\t\t%[4]s(&in.%[1]s[i], &out.%[2]s[i])\n\t\t}\n\t}", in, out, outType, call)
+	case ref.Map && ref.ElemPointer:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = make(map[string]*%[3]s, len(in.%[1]s))\n"+
+			"\t\tfor k, v := range in.%[1]s {\n\t\t\tif v == nil {\n\t\t\t\tout.%[2]s[k] = nil\n\t\t\t\tcontinue\n\t\t\t}\n"+
+			"\t\t\to := new(%[3]s)\n\t\t\t%[4]s(v, o)\n\t\t\tout.%[2]s[k] = o\n\t\t}\n\t}", in, out, outType, call)
+	case ref.Map:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = make(map[string]%[3]s, len(in.%[1]s))\n"+
+			"\t\tfor k, v := range in.%[1]s {\n\t\t\tvar o %[3]s\n\t\t\t%[4]s(&v, &o)\n\t\t\tout.%[2]s[k] = o\n\t\t}\n\t}",
+			in, out, outType, call)
+	default:
+		return fmt.Sprintf("%s(&in.%s, &out.%s)", call, in, out)
+	}
+}
+
+func convertValueStatement(in, out, outType string, ref typeRef) string {
+	switch {
+	case ref.Pointer:
+		return fmt.Sprintf("out.%s = (*%s)(in.%s)", out, outType, in)
+	case ref.Slice && ref.ElemPointer:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = make([]*%[3]s, len(in.%[1]s))\n"+
+			"\t\tfor i := range in.%[1]s {\n\t\t\tout.%[2]s[i] = (*%[3]s)(in.%[1]s[i])\n\t\t}\n\t}", in, out, outType)
+	case ref.Slice:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = make([]%[3]s, len(in.%[1]s))\n"+
+			"\t\tfor i := range in.%[1]s {\n\t\t\tout.%[2]s[i] = %[3]s(in.%[1]s[i])\n\t\t}\n\t}", in, out, outType)
+	case ref.Map && ref.ElemPointer:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = make(map[string]*%[3]s, len(in.%[1]s))\n"+
+			"\t\tfor k, v := range in.%[1]s {\n\t\t\tout.%[2]s[k] = (*%[3]s)(v)\n\t\t}\n\t}", in, out, outType)
+	case ref.Map:
+		return fmt.Sprintf("if in.%[1]s != nil {\n\t\tout.%[2]s = make(map[string]%[3]s, len(in.%[1]s))\n"+
+			"\t\tfor k, v := range in.%[1]s {\n\t\t\tout.%[2]s[k] = %[3]s(v)\n\t\t}\n\t}", in, out, outType)
+	default:
+		return fmt.Sprintf("out.%s = %s(in.%s)", out, outType, in)
+	}
+}
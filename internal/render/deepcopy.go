@@ -0,0 +1,214 @@
+package render
+
+import (
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+// deepCopyStruct is a struct with the statements of its DeepCopyInto method.
+type deepCopyStruct struct {
+	Name       string
+	Statements []string
+}
+
+// deepCopier creates the deep copy statements of the fields of the structs of a package.
+type deepCopier struct {
+	structs map[string]bool
+	enums   map[string]bool
+	// usesHelper is true if a field is copied with the generic deepCopyInto helper.
+	usesHelper bool
+	// packages are the names of the packages of the types used in the statements.
+	packages packageSet
+}
+
+// packageSet holds the names of the packages used by go type expressions.
+type packageSet map[string]bool
+
+// packageNamePattern matches the package names of a go type expression like map[string]corev1.Container.
+var packageNamePattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.[A-Za-z_]`)
+
+// generateDeepCopyCode generates the DeepCopy, DeepCopyInto and DeepCopyObject functions of all kinds and structs.
+func generateDeepCopyCode(resources *openapi.CustomResources, pkgName string) (string, error) {
+	structs := make(map[string]*openapi.StructDef)
+	c := &deepCopier{
+		structs:  make(map[string]bool),
+		enums:    make(map[string]bool),
+		packages: make(packageSet),
+	}
+	importSpecs := make(map[string]bool)
+	for _, cr := range resources.Items {
+		maps.Copy(structs, cr.Structs)
+		maps.Copy(importSpecs, cr.Imports)
+		for name := range cr.Enums {
+			c.enums[name] = true
+		}
+		c.structs[cr.Kind] = true
+	}
+	for name := range structs {
+		c.structs[name] = true
+	}
+
+	var kinds []deepCopyStruct
+	for _, cr := range resources.Items {
+		kinds = append(kinds, c.newDeepCopyStruct(cr.Root, true))
+	}
+	var copyStructs []deepCopyStruct
+	for _, name := range slices.Sorted(maps.Keys(structs)) {
+		copyStructs = append(copyStructs, c.newDeepCopyStruct(structs[name], false))
+	}
+
+	base := []string{`"k8s.io/apimachinery/pkg/runtime"`}
+	if c.usesHelper {
+		base = append(base, `"reflect"`)
+	}
+	imports := c.packages.imports(importSpecs, base...)
+
+	var sb strings.Builder
+	t := template.Must(template.New("deepcopy.go.tpl").Parse(deepCopyTpl))
+	err := t.Execute(&sb, map[string]any{
+		"AppName":    myName,
+		"Version":    resources.Version,
+		"Package":    pkgName,
+		"Imports":    imports,
+		"CRDNames":   resources.Names,
+		"Kinds":      kinds,
+		"Structs":    copyStructs,
+		"UsesHelper": c.usesHelper,
+	})
+	return sb.String(), err
+}
+
+func (c *deepCopier) newDeepCopyStruct(def *openapi.StructDef, root bool) deepCopyStruct {
+	d := deepCopyStruct{Name: def.Name}
+	for _, field := range def.Fields {
+		if root && isRootMetaField(field) {
+			continue
+		}
+		lines := c.copyInto("out."+field.Name, "in."+field.Name, field.Type, true)
+		if len(lines) > 0 {
+			d.Statements = append(d.Statements, strings.Join(lines, "\n\t"))
+		}
+	}
+	return d
+}
+
+// copyInto creates the statements deep copying the addressable expression in of the go type t into out.
+// If assigned is true, out already holds a shallow copy of in.
+func (c *deepCopier) copyInto(out, in, t string, assigned bool) []string {
+	switch {
+	case c.isShallow(t):
+		if assigned {
+			return nil
+		}
+		return []string{out + " = " + in}
+	case t == "any":
+		return block("if "+in+" != nil {", out+" = runtime.DeepCopyJSONValue("+in+")")
+	case strings.HasPrefix(t, "*"):
+		elem := t[1:]
+		lines := []string{"in, out := " + addr(in) + ", " + addr(out), "*out = new(" + c.typeExpr(elem) + ")"}
+		lines = append(lines, c.copyInto("**out", "**in", elem, false)...)
+		return block("if "+in+" != nil {", lines...)
+	case strings.HasPrefix(t, "[]"):
+		elem := t[2:]
+		lines := []string{"in, out := " + addr(in) + ", " + addr(out), "*out = make(" + c.typeExpr(t) + ", len(*in))"}
+		if c.isShallow(elem) {
+			lines = append(lines, "copy(*out, *in)")
+		} else {
+			lines = append(lines, block("for i := range *in {", c.copyInto("(*out)[i]", "(*in)[i]", elem, false)...)...)
+		}
+		return block("if "+in+" != nil {", lines...)
+	case strings.HasPrefix(t, "map[string]"):
+		elem := strings.TrimPrefix(t, "map[string]")
+		lines := []string{"in, out := " + addr(in) + ", " + addr(out), "*out = make(" + c.typeExpr(t) + ", len(*in))"}
+		var loop []string
+		if c.isShallow(elem) {
+			loop = []string{"(*out)[key] = val"}
+		} else {
+			// map values are not addressable, they are copied into a variable
+			loop = append([]string{"var outVal " + c.typeExpr(elem)}, c.copyInto("outVal", "val", elem, false)...)
+			loop = append(loop, "(*out)[key] = outVal")
+		}
+		lines = append(lines, block("for key, val := range *in {", loop...)...)
+		return block("if "+in+" != nil {", lines...)
+	case c.structs[t]:
+		return []string{recv(in) + ".DeepCopyInto(" + addr(out) + ")"}
+	default:
+		// the types of other packages are copied with their DeepCopyInto method if they have one
+		c.usesHelper = true
+		return []string{"deepCopyInto(" + addr(in) + ", " + addr(out) + ")"}
+	}
+}
+
+// typeExpr returns the type expression t, recording the packages it uses.
+func (c *deepCopier) typeExpr(t string) string {
+	return c.packages.typeExpr(t)
+}
+
+// typeExpr returns the type expression t, recording the packages it uses.
+func (p packageSet) typeExpr(t string) string {
+	for _, m := range packageNamePattern.FindAllStringSubmatch(t, -1) {
+		p[m[1]] = true
+	}
+	return t
+}
+
+// imports returns the base import specs and the specs of the used packages.
+func (p packageSet) imports(specs map[string]bool, base ...string) []string {
+	imports := base
+	for _, spec := range slices.Sorted(maps.Keys(specs)) {
+		if p[importName(spec)] && !slices.Contains(imports, spec) {
+			imports = append(imports, spec)
+		}
+	}
+	return imports
+}
+
+// importName returns the name of the package of an import spec, the alias or the last element of the path.
+func importName(spec string) string {
+	alias, importPath, found := strings.Cut(spec, " ")
+	if found {
+		return alias
+	}
+	importPath, err := strconv.Unquote(alias)
+	if err != nil {
+		return ""
+	}
+	return path.Base(importPath)
+}
+
+// isShallow checks if values of the type can be copied by assignment.
+func (c *deepCopier) isShallow(t string) bool {
+	return (isBuiltin(t) && t != "any") || c.enums[t]
+}
+
+// block creates the lines of a block with the indented statements.
+func block(head string, statements ...string) []string {
+	lines := []string{head}
+	for _, s := range statements {
+		lines = append(lines, "\t"+s)
+	}
+	return append(lines, "}")
+}
+
+// addr returns the address of an addressable expression.
+func addr(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return expr[1:]
+	}
+	return "&" + expr
+}
+
+// recv returns the expression as receiver of a method call.
+func recv(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
@@ -0,0 +1,133 @@
+package render
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+// defaulter is a SetDefaults_<Name> function applying the schema defaults to a struct.
+type defaulter struct {
+	Name       string
+	Statements []string
+}
+
+// generateDefaultsCode generates the defaulting functions of all kinds and the structs with defaults.
+func generateDefaultsCode(resources *openapi.CustomResources, pkgName string) (string, error) {
+	structs := make(map[string]*openapi.StructDef)
+	for _, cr := range resources.Items {
+		structs[cr.Kind] = cr.Root
+		maps.Copy(structs, cr.Structs)
+	}
+
+	needsDefaults := structsWithDefaults(structs)
+	kinds := kindNames(resources)
+
+	var defaulters []defaulter
+	for _, name := range slices.Sorted(maps.Keys(needsDefaults)) {
+		if kinds[name] {
+			continue
+		}
+		defaulters = append(defaulters, newDefaulter(structs[name], needsDefaults))
+	}
+
+	var kindDefaulters []defaulter
+	for _, cr := range resources.Items {
+		kindDefaulters = append(kindDefaulters, newDefaulter(cr.Root, needsDefaults))
+	}
+
+	var sb strings.Builder
+	t := template.Must(template.New("defaults.go.tpl").Parse(defaultsTpl))
+	err := t.Execute(&sb, map[string]any{
+		"AppName":    myName,
+		"Version":    resources.Version,
+		"Package":    pkgName,
+		"CRDNames":   resources.Names,
+		"Kinds":      kindDefaulters,
+		"Defaulters": defaulters,
+	})
+	return sb.String(), err
+}
+
+func kindNames(resources *openapi.CustomResources) map[string]bool {
+	names := make(map[string]bool)
+	for _, n := range resources.Names {
+		names[n.Kind] = true
+	}
+	return names
+}
+
+// structsWithDefaults evaluates the structs that have fields with defaults, or that contain such structs.
+func structsWithDefaults(structs map[string]*openapi.StructDef) map[string]bool {
+	needsDefaults := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for name, def := range structs {
+			if needsDefaults[name] {
+				continue
+			}
+			for _, field := range def.Fields {
+				ref := parseTypeRef(field.Type)
+				if (field.Default != "" && ref.isNilable()) || needsDefaults[ref.Name] {
+					needsDefaults[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return needsDefaults
+}
+
+// newDefaulter creates the statements of a defaulting function, collecting their errors in errs.
+// Only fields that can be nil are defaulted, as the zero value of other fields may be set explicitly.
+// Nested structs are defaulted if they are set, or if they have a default themselves.
+func newDefaulter(def *openapi.StructDef, needsDefaults map[string]bool) defaulter {
+	d := defaulter{Name: def.Name}
+	for _, field := range def.Fields {
+		ref := parseTypeRef(field.Type)
+		if field.Default != "" && ref.isNilable() {
+			d.Statements = append(d.Statements,
+				fmt.Sprintf("errs = append(errs, setDefault(&in.%s, %s))", field.Name, quoteRaw(field.Default)))
+		}
+
+		if !needsDefaults[ref.Name] {
+			continue
+		}
+		call := "SetDefaults_" + ref.Name
+		switch {
+		case ref.Pointer:
+			d.Statements = append(d.Statements,
+				fmt.Sprintf("if in.%[1]s != nil {\n\t\terrs = append(errs, %[2]s(in.%[1]s))\n\t}", field.Name, call))
+		case ref.ElemPointer:
+			d.Statements = append(d.Statements, fmt.Sprintf(
+				"for _, v := range in.%s {\n\t\tif v != nil {\n\t\t\terrs = append(errs, %s(v))\n\t\t}\n\t}", field.Name, call))
+		case ref.Slice:
+			d.Statements = append(d.Statements,
+				fmt.Sprintf("for i := range in.%[1]s {\n\t\terrs = append(errs, %[2]s(&in.%[1]s[i]))\n\t}", field.Name, call))
+		case ref.Map:
+			d.Statements = append(d.Statements, fmt.Sprintf(
+				"for k, v := range in.%[1]s {\n\t\terrs = append(errs, %[2]s(&v))\n\t\tin.%[1]s[k] = v\n\t}", field.Name, call))
+		case field.Default != "":
+			d.Statements = append(d.Statements, fmt.Sprintf("errs = append(errs, %s(&in.%s))", call, field.Name))
+		default:
+			// a struct with its zero value is treated as unset
+			d.Statements = append(d.Statements, fmt.Sprintf(
+				"if !reflect.ValueOf(in.%[1]s).IsZero() {\n\t\terrs = append(errs, %[2]s(&in.%[1]s))\n\t}", field.Name, call))
+		}
+	}
+	return d
+}
+
+// quoteRaw quotes a string as raw string literal if possible.
+func quoteRaw(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,35 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+func Test_newDefaulter(t *testing.T) {
+	structs := map[string]*openapi.StructDef{
+		"Spec": {Name: "Spec", Fields: []openapi.FieldDef{
+			{Name: "Enabled", Type: "bool", Default: "true"},
+			{Name: "Replicas", Type: "*int32", Default: "1"},
+			{Name: "Nested", Type: "Nested"},
+			{Name: "Defaulted", Type: "Nested", Default: "{}"},
+		}},
+		"Nested": {Name: "Nested", Fields: []openapi.FieldDef{
+			{Name: "Names", Type: "[]string", Default: `["a"]`},
+		}},
+		"Plain": {Name: "Plain", Fields: []openapi.FieldDef{
+			{Name: "Mode", Type: "string", Default: `"auto"`},
+		}},
+	}
+	needsDefaults := structsWithDefaults(structs)
+	assert.Equal(t, map[string]bool{"Spec": true, "Nested": true}, needsDefaults,
+		"fields that can not be nil are not defaulted")
+
+	assert.Equal(t, []string{
+		"errs = append(errs, setDefault(&in.Replicas, `1`))",
+		"if !reflect.ValueOf(in.Nested).IsZero() {\n\t\terrs = append(errs, SetDefaults_Nested(&in.Nested))\n\t}",
+		"errs = append(errs, SetDefaults_Nested(&in.Defaulted))",
+	}, newDefaulter(structs["Spec"], needsDefaults).Statements)
+}
@@ -0,0 +1,223 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSource is returned if a template renders code that is not valid go.
+var ErrInvalidSource = errors.New("generated code is not valid go")
+
+// SourceError is a syntax error in a rendered file, with the template and the position of the error.
+type SourceError struct {
+	// File is the path of the rendered file.
+	File string
+	// Template is the name of the template the file is rendered with.
+	Template string
+	// Line is the line of the error in the rendered file.
+	Line int
+	// Column is the column of the error in the rendered file.
+	Column int
+	// Code is the rendered line containing the error.
+	Code string
+	// Err is the syntax error.
+	Err error
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("%s: template %q rendered %s:%d:%d: %v\n\t%d | %s",
+		ErrInvalidSource, e.Template, e.File, e.Line, e.Column, e.Err, e.Line, e.Code)
+}
+
+func (e *SourceError) Unwrap() []error {
+	return []error{ErrInvalidSource, e.Err}
+}
+
+// formatFiles formats the go source of the files with gofmt and groups their imports.
+// The imports of the local import path are grouped last.
+func formatFiles(files []File, local string) error {
+	for i := range files {
+		content, err := formatSource(files[i].Name, []byte(files[i].Content), local)
+		if err != nil {
+			var list scanner.ErrorList
+			if errors.As(err, &list) && len(list) > 0 {
+				return newSourceError(files[i], list[0])
+			}
+			return fmt.Errorf("error formatting %s: %w", files[i].Name, err)
+		}
+		files[i].Content = string(content)
+	}
+	return nil
+}
+
+func newSourceError(f File, err *scanner.Error) *SourceError {
+	lines := strings.Split(f.Content, "\n")
+	var code string
+	if err.Pos.Line > 0 && err.Pos.Line <= len(lines) {
+		code = lines[err.Pos.Line-1]
+	}
+	return &SourceError{
+		File:     f.Name,
+		Template: f.template,
+		Line:     err.Pos.Line,
+		Column:   err.Pos.Column,
+		Code:     code,
+		Err:      errors.New(err.Msg),
+	}
+}
+
+// mergeFiles merges the go sources of several files of a package into one file with the given name.
+// The merged file has the header and package clause of the first file and the imports of all files.
+func mergeFiles(name string, files []File) (File, error) {
+	var header string
+	var imports []string
+	var body strings.Builder
+	for i, f := range files {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, f.Name, f.Content, parser.ImportsOnly|parser.ParseComments)
+		if err != nil {
+			var list scanner.ErrorList
+			if errors.As(err, &list) && len(list) > 0 {
+				return File{}, newSourceError(f, list[0])
+			}
+			return File{}, fmt.Errorf("error merging %s: %w", f.Name, err)
+		}
+
+		end := fset.Position(file.Name.End()).Offset
+		if i == 0 {
+			header = f.Content[:end]
+		}
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+				end = fset.Position(gen.End()).Offset
+			}
+		}
+		for _, imp := range file.Imports {
+			spec := imp.Path.Value
+			if imp.Name != nil {
+				spec = imp.Name.Name + " " + spec
+			}
+			if !slices.Contains(imports, spec) {
+				imports = append(imports, spec)
+			}
+		}
+		body.WriteString(f.Content[end:])
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	if len(imports) > 0 {
+		sb.WriteString("\nimport (\n")
+		for _, spec := range imports {
+			sb.WriteString("\t" + spec + "\n")
+		}
+		sb.WriteString(")\n")
+	}
+	sb.WriteString(body.String())
+
+	merged := files[0]
+	merged.Name = name
+	merged.Content = sb.String()
+	return merged, nil
+}
+
+// formatSource groups the imports of the source and formats it with gofmt.
+func formatSource(name string, src []byte, local string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(groupImports(fset, file, src, local))
+}
+
+// groupImports moves the standard library imports into the first group and the imports of the local import path
+// into the last group of each import declaration, like goimports -local does.
+// The other imports keep the groups they are rendered in, gofmt sorts the imports within each group.
+func groupImports(fset *token.FileSet, file *ast.File, src []byte, local string) []byte {
+	var out bytes.Buffer
+	last := 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT || !gen.Lparen.IsValid() {
+			continue
+		}
+		start := fset.Position(gen.Lparen).Offset + 1
+		end := fset.Position(gen.Rparen).Offset
+		out.Write(src[last:start])
+		out.WriteString(importBlock(fset, gen.Specs, src, local))
+		last = end
+	}
+	out.Write(src[last:])
+	return out.Bytes()
+}
+
+// importBlock renders the import specs with the standard library group first and the local group last.
+func importBlock(fset *token.FileSet, specs []ast.Spec, src []byte, local string) string {
+	var std, localGroup []string
+	var groups [][]string
+	lastLine := 0
+	for _, spec := range specs {
+		imp := spec.(*ast.ImportSpec)
+		from, to := imp.Pos(), imp.End()
+		if imp.Doc != nil {
+			from = imp.Doc.Pos()
+		}
+		if imp.Comment != nil {
+			to = imp.Comment.End()
+		}
+		text := string(src[fset.Position(from).Offset:fset.Position(to).Offset])
+
+		line := fset.Position(from).Line
+		newGroup := lastLine == 0 || line > lastLine+1
+		lastLine = fset.Position(to).Line
+
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err == nil && isStdLib(importPath) {
+			std = append(std, text)
+			continue
+		}
+		if err == nil && isLocalImport(importPath, local) {
+			localGroup = append(localGroup, text)
+			continue
+		}
+		if newGroup || len(groups) == 0 {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], text)
+	}
+
+	groups = append(append([][]string{std}, groups...), localGroup)
+	groups = slices.DeleteFunc(groups, func(g []string) bool { return len(g) == 0 })
+	var sb strings.Builder
+	sb.WriteString("\n")
+	for i, group := range groups {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, text := range group {
+			sb.WriteString("\t" + text + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// isLocalImport checks if the import path is the local import path or one of its sub packages.
+func isLocalImport(importPath, local string) bool {
+	return local != "" && (importPath == local || strings.HasPrefix(importPath, local+"/"))
+}
+
+// isStdLib checks if the import path is a package of the standard library, which has no dot in its first element.
+func isStdLib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,159 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatFiles(t *testing.T) {
+	files := []File{{Name: "v1/types_widget.go", Content: `package v1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"example.com/api/v3"
+	"example.com/apis/v1"
+
+	hub "example.com/api/v2"
+	"encoding/json"
+)
+
+type Widget struct {
+	metav1.TypeMeta
+	Spec runtime.RawExtension ` + "`json:\"spec\"`" + `
+	Raw json.RawMessage
+	Hub *hub.Widget
+	Next *v3.Widget
+	Other *v1.Widget
+}
+
+var _ = fmt.Sprint
+`}}
+
+	require.NoError(t, formatFiles(files, "example.com/api"))
+	assert.Equal(t, `package v1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"example.com/apis/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	hub "example.com/api/v2"
+	"example.com/api/v3"
+)
+
+type Widget struct {
+	metav1.TypeMeta
+	Spec  runtime.RawExtension `+"`json:\"spec\"`"+`
+	Raw   json.RawMessage
+	Hub   *hub.Widget
+	Next  *v3.Widget
+	Other *v1.Widget
+}
+
+var _ = fmt.Sprint
+`, files[0].Content)
+}
+
+func Test_formatFiles_invalid(t *testing.T) {
+	files := []File{{Name: "v1/types_widget.go", template: "types.go.tpl", Content: `package v1
+
+type Widget struct {
+	Name string
+}}
+`}}
+
+	err := formatFiles(files, "")
+	require.ErrorIs(t, err, ErrInvalidSource)
+
+	var srcErr *SourceError
+	require.ErrorAs(t, err, &srcErr)
+	assert.Equal(t, "types.go.tpl", srcErr.Template)
+	assert.Equal(t, "v1/types_widget.go", srcErr.File)
+	assert.Equal(t, 5, srcErr.Line)
+	assert.Equal(t, "}}", srcErr.Code)
+	assert.Contains(t, err.Error(), `template "types.go.tpl" rendered v1/types_widget.go:5:2`)
+}
+
+func Test_mergeFiles(t *testing.T) {
+	files := []File{
+		{Name: "v1/types_a.go", template: "types.go.tpl", Content: `// Code generated. DO NOT EDIT.
+
+package v1
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type A struct {
+	metav1.TypeMeta
+	Raw json.RawMessage
+}
+`},
+		{Name: "v1/types_b.go", template: "types.go.tpl", Content: `// Code generated. DO NOT EDIT.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type B struct {
+	metav1.TypeMeta
+	Port intstr.IntOrString
+}
+`},
+	}
+
+	merged, err := mergeFiles("v1/types.go", files)
+	require.NoError(t, err)
+	assert.Equal(t, "v1/types.go", merged.Name)
+	assert.Equal(t, "types.go.tpl", merged.template)
+
+	formatted := []File{merged}
+	require.NoError(t, formatFiles(formatted, ""))
+	assert.Equal(t, `// Code generated. DO NOT EDIT.
+
+package v1
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type A struct {
+	metav1.TypeMeta
+	Raw json.RawMessage
+}
+
+type B struct {
+	metav1.TypeMeta
+	Port intstr.IntOrString
+}
+`, formatted[0].Content)
+}
+
+func Test_mergeFiles_invalid(t *testing.T) {
+	files := []File{
+		{Name: "v1/types_a.go", template: "types.go.tpl", Content: "package v1\n\ntype A struct{}\n"},
+		{Name: "v1/types_b.go", template: "types.go.tpl", Content: "package v1\n\nimport (\n\t\"fmt\"\n"},
+	}
+
+	_, err := mergeFiles("v1/types.go", files)
+	require.ErrorIs(t, err, ErrInvalidSource)
+
+	var sourceErr *SourceError
+	require.ErrorAs(t, err, &sourceErr)
+	assert.Equal(t, "v1/types_b.go", sourceErr.File)
+}
@@ -1,6 +1,6 @@
 // Code generated by {{ .AppName }}. DO NOT EDIT.
 
-package {{ .Version }}
+package {{ .Package }}
 
 // +kubebuilder:object:generate=true
 
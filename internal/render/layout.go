@@ -0,0 +1,131 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+// Layout defines the directories the group version packages are written to.
+type Layout string
+
+const (
+	// LayoutDefault writes each version to <target>/<version>. If the packages have different groups,
+	// each group is written to its own <target>/<group-short>/<version> directory.
+	LayoutDefault Layout = ""
+	// LayoutGroupVersion writes each package to <target>/<group-short>/<version>, also for a single group.
+	LayoutGroupVersion Layout = "group-version"
+	// LayoutFlat writes a single group version package directly into the target directory.
+	LayoutFlat Layout = "flat"
+)
+
+// DefaultTypesFile is the default file name pattern of the types files, a file per kind.
+const DefaultTypesFile = "types_{kind}.go"
+
+var (
+	// ErrInvalidPackageName is returned if the package name is not a go identifier.
+	ErrInvalidPackageName = errors.New("invalid package name")
+	// ErrInvalidLayout is returned if the layout is unknown or can not hold all packages.
+	ErrInvalidLayout = errors.New("invalid layout")
+	// ErrInvalidFileName is returned if the types file name pattern is not a go file name,
+	// or if several files are rendered to the same path.
+	ErrInvalidFileName = errors.New("invalid file name")
+)
+
+// validateOptions checks the package name, the layout and the types file name pattern.
+func validateOptions(packages []*openapi.CustomResources, opts Options) error {
+	if opts.Package != "" && !token.IsIdentifier(opts.Package) {
+		return fmt.Errorf("%w %q: the package name must be a go identifier", ErrInvalidPackageName, opts.Package)
+	}
+
+	switch opts.Layout {
+	case LayoutDefault, LayoutGroupVersion:
+	case LayoutFlat:
+		if len(packages) > 1 {
+			return fmt.Errorf("%w %q: %d group version packages can not be written into one directory, "+
+				"select a single group and version", ErrInvalidLayout, opts.Layout, len(packages))
+		}
+	default:
+		return fmt.Errorf("%w %q: must be %q or %q", ErrInvalidLayout, opts.Layout, LayoutGroupVersion, LayoutFlat)
+	}
+
+	if opts.TypesFile != "" && (filepath.Base(opts.TypesFile) != opts.TypesFile || filepath.Ext(opts.TypesFile) != ".go") {
+		return fmt.Errorf("%w %q: the types file must be a .go file name without directory", ErrInvalidFileName, opts.TypesFile)
+	}
+	return nil
+}
+
+// packageName returns the go package name of a group version package, the version if no package name is defined.
+func packageName(res *openapi.CustomResources, opts Options) string {
+	if opts.Package != "" {
+		return opts.Package
+	}
+	return res.Version
+}
+
+// typesFileName returns the name of the types file of a kind.
+// The placeholders {kind} (lower case kind), {Kind} and {version} of the pattern are replaced,
+// all kinds of a package with the same file name are combined into one file.
+func typesFileName(pattern string, cr *openapi.CustomResource, version string) string {
+	if pattern == "" {
+		pattern = DefaultTypesFile
+	}
+	return strings.NewReplacer(
+		"{kind}", strings.ToLower(cr.Kind),
+		"{Kind}", cr.Kind,
+		"{version}", version,
+	).Replace(pattern)
+}
+
+// packageDirs evaluates the directory of each group version package according to the layout.
+// With the default layout, each group is written to its own <group-short>/<version> directory
+// if the packages have different groups.
+func packageDirs(packages []*openapi.CustomResources, targetDir string, layout Layout) map[*openapi.CustomResources]string {
+	dirs := make(map[*openapi.CustomResources]string)
+	if layout == LayoutFlat {
+		for _, res := range packages {
+			dirs[res] = targetDir
+		}
+		return dirs
+	}
+
+	groups := make(map[string]bool)
+	for _, res := range packages {
+		groups[res.Group] = true
+	}
+
+	groupDirs := make(map[string]string)
+	if len(groups) > 1 || layout == LayoutGroupVersion {
+		shortNames := make(map[string]int)
+		for group := range groups {
+			shortNames[groupShortName(group)]++
+		}
+		for group := range groups {
+			groupDirs[group] = groupShortName(group)
+			if shortNames[groupDirs[group]] > 1 {
+				// the short name is not unique, use the full group name
+				groupDirs[group] = group
+			}
+		}
+	}
+
+	for _, res := range packages {
+		dirs[res] = filepath.Join(targetDir, groupDirs[res.Group], res.Version)
+	}
+	return dirs
+}
+
+// groupShortName returns the first label of the group, reduced to lower case letters and digits.
+func groupShortName(group string) string {
+	label, _, _ := strings.Cut(group, ".")
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, strings.ToLower(label))
+}
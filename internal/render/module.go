@@ -0,0 +1,81 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// packageImportPath evaluates the go import path of a package directory in the target directory.
+// If the import path of the target directory is not defined, it is evaluated from the enclosing go module.
+func packageImportPath(targetDir, pkgDir, targetImportPath string) (string, error) {
+	if targetImportPath == "" {
+		return goPackagePath(pkgDir)
+	}
+	rel, err := filepath.Rel(targetDir, pkgDir)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(targetImportPath, filepath.ToSlash(rel)), nil
+}
+
+// goPackagePath evaluates the go import path of a directory, based on the go.mod file of the enclosing module.
+func goPackagePath(dir string) (string, error) {
+	modDir, modulePath, err := goModule(dir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(modDir, abs)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(modulePath, filepath.ToSlash(rel)), nil
+}
+
+// localImportPath returns the import path prefix of the packages local to the target directory: the import path
+// of the target directory if defined, otherwise the path of the enclosing module. It is empty if both are unknown.
+func localImportPath(targetDir, targetImportPath string) string {
+	if targetImportPath != "" {
+		return targetImportPath
+	}
+	if _, modulePath, err := goModule(targetDir); err == nil {
+		return modulePath
+	}
+	return ""
+}
+
+// goModule returns the directory and the module path of the go module enclosing the directory.
+func goModule(dir string) (modDir, modulePath string, err error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for modDir = abs; ; {
+		data, err := os.ReadFile(filepath.Join(modDir, "go.mod"))
+		if err == nil {
+			modulePath = modfile.ModulePath(data)
+			if modulePath == "" {
+				return "", "", fmt.Errorf("no module path defined in %s", filepath.Join(modDir, "go.mod"))
+			}
+			return modDir, modulePath, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", "", err
+		}
+
+		parent := filepath.Dir(modDir)
+		if parent == modDir {
+			return "", "", fmt.Errorf("could not find a go.mod file for %s", dir)
+		}
+		modDir = parent
+	}
+}
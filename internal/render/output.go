@@ -22,22 +22,147 @@ var (
 	gviTpl string
 	//go:embed types.go.tpl
 	typeTpl string
+	//go:embed defaults.go.tpl
+	defaultsTpl string
+	//go:embed cel.go.tpl
+	celTpl string
+	//go:embed conversion.go.tpl
+	conversionTpl string
+	//go:embed deepcopy.go.tpl
+	deepCopyTpl string
+	//go:embed applyconfiguration.go.tpl
+	applyConfigTpl string
+	//go:embed applyconfiguration_schema.go.tpl
+	applyConfigSchemaTpl string
+	//go:embed clientset.go.tpl
+	clientsetTpl string
+	//go:embed listers.go.tpl
+	listersTpl string
+	//go:embed informers.go.tpl
+	informersTpl string
 )
 
-func WriteCrdFiles(ctx context.Context, resources *openapi.CustomResources, targetDir string) error {
-	var files []outFile
+// Options define the optional files to be generated.
+type Options struct {
+	// CELValidation generates ValidateCEL methods evaluating the x-kubernetes-validations rules.
+	CELValidation bool
+	// Conversion generates the conversion functions between the versions of a kind.
+	Conversion bool
+	// HubVersion is the hub version of the conversions. If not defined, the storage version is the hub.
+	HubVersion string
+	// ApplyConfigurations generates the server-side apply configurations of the types into
+	// the applyconfiguration directory.
+	ApplyConfigurations bool
+	// Clients generates the typed clientset, the listers and the informers of the types into
+	// the clientset, listers and informers directories.
+	Clients bool
+	// Package is the go package name of the group version packages. If not defined, the version is used.
+	Package string
+	// Layout defines the directories the group version packages are written to.
+	Layout Layout
+	// TypesFile is the file name pattern of the types files, see DefaultTypesFile.
+	// Kinds with the same file name are combined into one file, e.g. all kinds of a package with types.go.
+	TypesFile string
+	// TemplateDir is the directory of user templates. The types.go.tpl and group_version_into.go.tpl templates
+	// replace the built-in ones, the templates of the kind and group sub directories render additional files
+	// per kind and per group version.
+	TemplateDir string
+	// ImportPath is the go import path of the target directory, used to import the hub packages of conversions
+	// and the types of apply configurations and clients.
+	// If not defined, it is evaluated from the go.mod file of the enclosing module.
+	ImportPath string
+}
+
+func WriteCrdFiles(ctx context.Context, packages []*openapi.CustomResources, targetDir string, opts Options) error {
+	files, err := Render(packages, targetDir, opts)
+	if err != nil {
+		return err
+	}
+	return WriteFiles(ctx, files)
+}
+
+// Render renders the files of all packages in memory. The file names are located in the target directory.
+func Render(packages []*openapi.CustomResources, targetDir string, opts Options) ([]File, error) {
+	if err := validateOptions(packages, opts); err != nil {
+		return nil, err
+	}
+	tpls, err := loadTemplates(opts.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	pkgDirs := packageDirs(packages, targetDir, opts.Layout)
+	for _, resources := range packages {
+		pkgFiles, err := renderPackage(resources, pkgDirs[resources], tpls, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, pkgFiles...)
+	}
+
+	if opts.Conversion {
+		convFiles, err := renderConversions(packages, pkgDirs, targetDir, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, convFiles...)
+	}
+
+	if opts.ApplyConfigurations {
+		applyFiles, err := renderApplyConfigurations(packages, pkgDirs, targetDir, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, applyFiles...)
+	}
+
+	if opts.Clients {
+		clientFiles, err := renderClients(packages, pkgDirs, targetDir, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, clientFiles...)
+	}
+
+	names := make(map[string]bool)
+	for _, f := range files {
+		if names[f.Name] {
+			return nil, fmt.Errorf("%w: %s is rendered by several templates", ErrInvalidFileName, f.Name)
+		}
+		names[f.Name] = true
+	}
+
+	if err := formatFiles(files, localImportPath(targetDir, opts.ImportPath)); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// renderPackage renders all files of a group version package into the package dir.
+func renderPackage(
+	resources *openapi.CustomResources,
+	pkgDir string,
+	tpls *packageTemplates,
+	opts Options,
+) ([]File, error) {
+	pkgName := packageName(resources, opts)
+	var files, typesFiles []File
 	for _, cr := range resources.Items {
+		data := typesData(cr, resources.Group, resources.Version, pkgName)
+
 		// Generate types code
-		typesCode, err := generateTypesCode(cr, resources.Group, resources.Version)
+		typesCode, err := executeTemplate(tpls.types, data)
 		if err != nil {
-			return fmt.Errorf("error generating types content: %w", err)
+			return nil, fmt.Errorf("error generating types content: %w", err)
 		}
 
 		// Write output file
-		outputFile := filepath.Join(targetDir, resources.Version, fmt.Sprintf("types_%s.go", strings.ToLower(cr.Kind)))
-		files = append(files, outFile{
-			name:       outputFile,
-			content:    typesCode,
+		outputFile := filepath.Join(pkgDir, typesFileName(opts.TypesFile, cr, resources.Version))
+		typesFiles = append(typesFiles, File{
+			Name:       outputFile,
+			Content:    typesCode,
+			template:   tpls.types.Name(),
 			successMsg: "Successfully generated Go structs",
 			successArgs: []any{
 				"group", resources.Group,
@@ -46,39 +171,155 @@ func WriteCrdFiles(ctx context.Context, resources *openapi.CustomResources, targ
 				"file", outputFile,
 			},
 		})
+
+		// Generate the additional files of the kind
+		for _, t := range tpls.kind {
+			content, err := executeTemplate(t, data)
+			if err != nil {
+				return nil, fmt.Errorf("error generating %s content: %w", t.Name(), err)
+			}
+
+			outputFile := filepath.Join(pkgDir, fmt.Sprintf("%s_%s.go", templateBaseName(t), strings.ToLower(cr.Kind)))
+			files = append(files, File{
+				Name:       outputFile,
+				Content:    content,
+				template:   t.Name(),
+				successMsg: "Successfully generated kind template",
+				successArgs: []any{
+					"group", resources.Group,
+					"version", resources.Version,
+					"kind", cr.Kind,
+					"template", t.Name(),
+					"file", outputFile,
+				},
+			})
+		}
+	}
+
+	// Combine the kinds with the same types file
+	for _, name := range uniqueNames(typesFiles) {
+		kindFiles := slices.DeleteFunc(slices.Clone(typesFiles), func(f File) bool { return f.Name != name })
+		if len(kindFiles) == 1 {
+			files = append(files, kindFiles[0])
+			continue
+		}
+		merged, err := mergeFiles(name, kindFiles)
+		if err != nil {
+			return nil, err
+		}
+		merged.successArgs = []any{"group", resources.Group, "version", resources.Version, "file", name}
+		files = append(files, merged)
 	}
 
 	// Generate GroupVersionInfo code
-	gvi, err := generateGroupVersionInfoCode(resources)
+	data := groupVersionInfoData(resources, pkgName)
+	gvi, err := executeTemplate(tpls.groupVersionInfo, data)
 	if err != nil {
-		return fmt.Errorf("error writing group_version_kind.go: %w", err)
+		return nil, fmt.Errorf("error writing group_version_kind.go: %w", err)
 	}
 
 	// Write output file
-	outputFile := filepath.Join(targetDir, resources.Version, "group_version_info.go")
+	outputFile := filepath.Join(pkgDir, "group_version_info.go")
 
-	files = append(files, outFile{
-		name:       outputFile,
-		content:    gvi,
+	files = append(files, File{
+		Name:       outputFile,
+		Content:    gvi,
+		template:   tpls.groupVersionInfo.Name(),
 		successMsg: "Successfully generated GroupVersionInfo",
 		successArgs: []any{
 			"group", resources.Group, "version", resources.Version, "file", outputFile,
 		},
 	})
 
-	return writeFiles(ctx, files)
+	// Generate the additional files of the group version
+	for _, t := range tpls.group {
+		content, err := executeTemplate(t, data)
+		if err != nil {
+			return nil, fmt.Errorf("error generating %s content: %w", t.Name(), err)
+		}
+
+		outputFile := filepath.Join(pkgDir, templateBaseName(t)+".go")
+		files = append(files, File{
+			Name:       outputFile,
+			Content:    content,
+			template:   t.Name(),
+			successMsg: "Successfully generated group template",
+			successArgs: []any{
+				"group", resources.Group,
+				"version", resources.Version,
+				"template", t.Name(),
+				"file", outputFile,
+			},
+		})
+	}
+
+	// Generate defaulting code
+	defaults, err := generateDefaultsCode(resources, pkgName)
+	if err != nil {
+		return nil, fmt.Errorf("error generating defaults content: %w", err)
+	}
+
+	outputFile = filepath.Join(pkgDir, "zz_generated.defaults.go")
+	files = append(files, File{
+		Name:       outputFile,
+		Content:    defaults,
+		template:   "defaults.go.tpl",
+		successMsg: "Successfully generated defaulters",
+		successArgs: []any{
+			"group", resources.Group, "version", resources.Version, "file", outputFile,
+		},
+	})
+
+	// Generate deep copy code
+	deepCopy, err := generateDeepCopyCode(resources, pkgName)
+	if err != nil {
+		return nil, fmt.Errorf("error generating deep copy content: %w", err)
+	}
+
+	outputFile = filepath.Join(pkgDir, "zz_generated.deepcopy.go")
+	files = append(files, File{
+		Name:       outputFile,
+		Content:    deepCopy,
+		template:   "deepcopy.go.tpl",
+		successMsg: "Successfully generated deep copy functions",
+		successArgs: []any{
+			"group", resources.Group, "version", resources.Version, "file", outputFile,
+		},
+	})
+
+	if opts.CELValidation {
+		// Generate CEL validation code
+		celCode, err := generateCELCode(resources, pkgName)
+		if err != nil {
+			return nil, fmt.Errorf("error generating CEL validation content: %w", err)
+		}
+
+		outputFile = filepath.Join(pkgDir, "zz_generated.cel.go")
+		files = append(files, File{
+			Name:       outputFile,
+			Content:    celCode,
+			template:   "cel.go.tpl",
+			successMsg: "Successfully generated CEL validation",
+			successArgs: []any{
+				"group", resources.Group, "version", resources.Version, "file", outputFile,
+			},
+		})
+	}
+
+	return files, nil
 }
 
-func writeFiles(ctx context.Context, files []outFile) error {
+// WriteFiles writes the rendered files.
+func WriteFiles(ctx context.Context, files []File) error {
 	for _, f := range files {
-		dir := filepath.Dir(f.name)
+		dir := filepath.Dir(f.Name)
 
 		// Create the directory if it doesn't exist
 		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 			return fmt.Errorf("error creating directory: %w", err)
 		}
 
-		if err := os.WriteFile(f.name, []byte(f.content), 0o644); err != nil {
+		if err := os.WriteFile(f.Name, []byte(f.Content), 0o644); err != nil {
 			return fmt.Errorf("error writing output file: %w", err)
 		}
 
@@ -87,15 +328,32 @@ func writeFiles(ctx context.Context, files []outFile) error {
 	return nil
 }
 
-type outFile struct {
-	name        string
-	content     string
+// File is a rendered file.
+type File struct {
+	// Name is the path of the file.
+	Name string
+	// Content is the go source of the file.
+	Content string
+
+	// template is the name of the template the file is rendered with.
+	template    string
 	successMsg  string
 	successArgs []any
 }
 
-// Generate Go code from struct definitions.
-func generateTypesCode(cr *openapi.CustomResource, group, version string) (string, error) {
+// uniqueNames returns the distinct file names in the order of the files.
+func uniqueNames(files []File) []string {
+	var names []string
+	for _, f := range files {
+		if !slices.Contains(names, f.Name) {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// typesData returns the data of the types template and the kind templates.
+func typesData(cr *openapi.CustomResource, group, version, pkgName string) map[string]any {
 	// Sort and generate structs
 	sortedStructNames := slices.Sorted(maps.Keys(cr.Structs))
 
@@ -103,42 +361,46 @@ func generateTypesCode(cr *openapi.CustomResource, group, version string) (strin
 
 	importList := slices.Sorted(maps.Keys(cr.Imports))
 
-	prepare(cr.Root)
+	root := prepare(cr.Root)
 
 	// keep only spec and status
-	var rootFields []openapi.FieldDef
-	for _, field := range cr.Root.Fields {
-		if field.JSONTag != "apiVersion" && field.JSONTag != "kind" && field.JSONTag != "metadata" {
-			rootFields = append(rootFields, field)
-		}
-	}
-	cr.Root.Fields = rootFields
+	root.Fields = slices.DeleteFunc(root.Fields, isRootMetaField)
 
 	for _, structName := range sortedStructNames {
-		structDef := cr.Structs[structName]
-		prepare(structDef)
+		structs = append(structs, prepare(cr.Structs[structName]))
+	}
 
-		structs = append(structs, structDef)
+	var enums []*openapi.EnumTypeDef
+	for _, enumName := range slices.Sorted(maps.Keys(cr.Enums)) {
+		enums = append(enums, cr.Enums[enumName])
 	}
 
-	var sb strings.Builder
-	t := template.Must(template.New("types.go.tpl").Parse(typeTpl))
-	err := t.Execute(&sb, map[string]any{
+	return map[string]any{
 		"AppName": myName,
 		"Version": version,
+		"Package": pkgName,
 		"Group":   group,
 		"Kind":    cr.Kind,
 		"List":    cr.List,
 		"Plural":  openapi.ToCamelCase(cr.Plural),
-		"Root":    cr.Root,
+		"Root":    root,
 		"Structs": structs,
+		"Enums":   enums,
 		"Imports": importList,
-	})
-	return sb.String(), err
+	}
+}
+
+// isRootMetaField checks if the root field is rendered as the embedded metav1.TypeMeta or metav1.ObjectMeta.
+func isRootMetaField(field openapi.FieldDef) bool {
+	return field.JSONTag == "apiVersion" || field.JSONTag == "kind" || field.JSONTag == "metadata"
 }
 
-func prepare(structDef *openapi.StructDef) {
-	structDef.Description = prepareDescription(structDef.Description, false)
+// prepare returns a copy of the struct for rendering, with sorted fields and comment descriptions.
+// The parsed struct is not modified, so the resources can be rendered several times.
+func prepare(def *openapi.StructDef) *openapi.StructDef {
+	structDef := *def
+	structDef.Description = prepareDescription(def.Description, false)
+	structDef.Fields = slices.Clone(def.Fields)
 
 	slices.SortFunc(structDef.Fields, func(a, b openapi.FieldDef) int {
 		return strings.Compare(a.Name, b.Name)
@@ -147,6 +409,7 @@ func prepare(structDef *openapi.StructDef) {
 	for i, f := range structDef.Fields {
 		structDef.Fields[i].Description = prepareDescription(f.Description, true)
 	}
+	return &structDef
 }
 
 func prepareDescription(desc string, field bool) string {
@@ -157,17 +420,22 @@ func prepareDescription(desc string, field bool) string {
 	return strings.ReplaceAll(desc, "\n", "\n"+indent)
 }
 
-func generateGroupVersionInfoCode(res *openapi.CustomResources) (string, error) {
-	var sb strings.Builder
-	t := template.Must(template.New("group_version_into.go.tpl").Parse(gviTpl))
-	if err := t.Execute(&sb, map[string]any{
+// groupVersionInfoData returns the data of the group version info template and the group templates.
+func groupVersionInfoData(res *openapi.CustomResources, pkgName string) map[string]any {
+	return map[string]any{
 		"AppName":  myName,
 		"Version":  res.Version,
+		"Package":  pkgName,
 		"Group":    res.Group,
 		"CRDNames": res.Names,
-	}); err != nil {
-		return "", err
 	}
+}
 
+// executeTemplate renders the template with the data.
+func executeTemplate(t *template.Template, data any) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", err
+	}
 	return sb.String(), nil
 }
@@ -0,0 +1,47 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+)
+
+func Test_typesData_doesNotModifyResource(t *testing.T) {
+	cr := &openapi.CustomResource{
+		Kind: "Foo",
+		Root: &openapi.StructDef{Name: "Foo", Description: "Foo is\na resource", Fields: []openapi.FieldDef{
+			{Name: "Status", Type: "FooStatus"},
+			{Name: "Metadata", Type: "metav1.ObjectMeta", JSONTag: "metadata"},
+			{Name: "Spec", Type: "FooSpec", Description: "Spec is\nthe spec"},
+		}},
+		Structs: map[string]*openapi.StructDef{
+			"FooSpec": {Name: "FooSpec", Fields: []openapi.FieldDef{
+				{Name: "Replicas", Type: "*int32", Description: "Replicas\nto run"},
+				{Name: "Image", Type: "string"},
+			}},
+		},
+	}
+
+	first := typesData(cr, "example.com", "v1", "v1")
+	second := typesData(cr, "example.com", "v1", "v1")
+	assert.Equal(t, first, second)
+
+	assert.Equal(t, "Foo is\na resource", cr.Root.Description)
+	assert.Equal(t, []string{"Status", "Metadata", "Spec"}, fieldNames(cr.Root))
+	assert.Equal(t, "Spec is\nthe spec", cr.Root.Fields[2].Description)
+	assert.Equal(t, []string{"Replicas", "Image"}, fieldNames(cr.Structs["FooSpec"]))
+	assert.Equal(t, "Replicas\nto run", cr.Structs["FooSpec"].Fields[0].Description)
+
+	root := first["Root"].(*openapi.StructDef)
+	assert.Equal(t, []string{"Spec", "Status"}, fieldNames(root))
+}
+
+func fieldNames(structDef *openapi.StructDef) []string {
+	var names []string
+	for _, f := range structDef.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
@@ -1,12 +1,38 @@
 package render
 
 import (
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	applymetav1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/gentype"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
+	"sigs.k8s.io/structured-merge-diff/v6/typed"
 )
 
 var (
 	// verifies that all required dependencies for the application are correctly set up and available.
 	_ = scheme.Builder{} //nolint:staticcheck
 	_ = schema.GroupVersion{}
+	_ = cel.Env{}
+	_ = ext.Strings
+	_ = field.Path{}
+	_ = corev1.Toleration{}
+	_ conversion.Hub
+	_ = managedfields.ExtractInto
+	_ = applymetav1.ObjectMetaApplyConfiguration{}
+	_ = typed.NewParser
+	_ = discovery.NewDiscoveryClient
+	_ = gentype.NewClientWithList[*corev1.Pod, *corev1.PodList]
+	_ = listers.New[*corev1.Pod]
+	_ = cache.NewSharedIndexInformer
+	_ = flowcontrol.NewTokenBucketRateLimiter
 )
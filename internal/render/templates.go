@@ -0,0 +1,116 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	typesTemplate            = "types.go.tpl"
+	groupVersionInfoTemplate = "group_version_into.go.tpl"
+	// kindTemplatesDir is the directory of the user templates rendered per kind.
+	kindTemplatesDir = "kind"
+	// groupTemplatesDir is the directory of the user templates rendered per group version.
+	groupTemplatesDir = "group"
+	templateSuffix    = ".go.tpl"
+)
+
+// ErrUnknownTemplate is returned if the template directory contains a template that neither overrides
+// a built-in template, nor is an additional kind or group template.
+var ErrUnknownTemplate = errors.New("unknown template")
+
+// packageTemplates are the templates rendering the files of a group version package.
+type packageTemplates struct {
+	types            *template.Template
+	groupVersionInfo *template.Template
+	// kind are the additional user templates rendered per kind.
+	kind []*template.Template
+	// group are the additional user templates rendered per group version.
+	group []*template.Template
+}
+
+// loadTemplates parses the package templates. Templates in the template directory replace the built-in ones,
+// the templates of its kind and group directories render additional files.
+func loadTemplates(templateDir string) (*packageTemplates, error) {
+	tpls := &packageTemplates{
+		types:            template.Must(template.New(typesTemplate).Parse(typeTpl)),
+		groupVersionInfo: template.Must(template.New(groupVersionInfoTemplate).Parse(gviTpl)),
+	}
+	if templateDir == "" {
+		return tpls, nil
+	}
+
+	entries, err := os.ReadDir(templateDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template directory: %w", err)
+	}
+	for _, e := range entries {
+		switch {
+		case e.IsDir() && e.Name() == kindTemplatesDir:
+			if tpls.kind, err = parseTemplates(templateDir, kindTemplatesDir); err != nil {
+				return nil, err
+			}
+		case e.IsDir() && e.Name() == groupTemplatesDir:
+			if tpls.group, err = parseTemplates(templateDir, groupTemplatesDir); err != nil {
+				return nil, err
+			}
+		case e.Name() == typesTemplate:
+			if tpls.types, err = parseTemplate(templateDir, typesTemplate); err != nil {
+				return nil, err
+			}
+		case e.Name() == groupVersionInfoTemplate:
+			if tpls.groupVersionInfo, err = parseTemplate(templateDir, groupVersionInfoTemplate); err != nil {
+				return nil, err
+			}
+		case strings.HasSuffix(e.Name(), templateSuffix):
+			return nil, fmt.Errorf(
+				"%w %q: only %s and %s can be overridden, additional templates belong to the %s or %s directory",
+				ErrUnknownTemplate, filepath.Join(templateDir, e.Name()),
+				typesTemplate, groupVersionInfoTemplate, kindTemplatesDir, groupTemplatesDir)
+		}
+	}
+	return tpls, nil
+}
+
+// parseTemplates parses all templates of the sub directory of the template directory, sorted by name.
+func parseTemplates(templateDir, dir string) ([]*template.Template, error) {
+	entries, err := os.ReadDir(filepath.Join(templateDir, dir))
+	if err != nil {
+		return nil, fmt.Errorf("error reading template directory: %w", err)
+	}
+	var tpls []*template.Template
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), templateSuffix) {
+			continue
+		}
+		t, err := parseTemplate(templateDir, filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		tpls = append(tpls, t)
+	}
+	return tpls, nil
+}
+
+// parseTemplate parses the template file, named by its path relative to the template directory.
+func parseTemplate(templateDir, name string) (*template.Template, error) {
+	content, err := os.ReadFile(filepath.Join(templateDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("error reading template: %w", err)
+	}
+	t, err := template.New(filepath.ToSlash(name)).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %w", err)
+	}
+	return t, nil
+}
+
+// templateBaseName returns the file name of a user template without the .go.tpl suffix.
+func templateBaseName(t *template.Template) string {
+	return strings.TrimSuffix(path.Base(t.Name()), templateSuffix)
+}
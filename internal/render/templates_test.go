@@ -0,0 +1,47 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, kindTemplatesDir), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, typesTemplate), []byte("package {{ .Version }}"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kindTemplatesDir, "b.go.tpl"), []byte("b"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kindTemplatesDir, "a.go.tpl"), []byte("a"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kindTemplatesDir, "README.md"), []byte("docs"), 0o600))
+
+	tpls, err := loadTemplates(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, typesTemplate, tpls.types.Name())
+	assert.Equal(t, groupVersionInfoTemplate, tpls.groupVersionInfo.Name(), "the built-in template is kept")
+	require.Len(t, tpls.kind, 2)
+	assert.Equal(t, "kind/a.go.tpl", tpls.kind[0].Name())
+	assert.Equal(t, "a", templateBaseName(tpls.kind[0]))
+	assert.Equal(t, "kind/b.go.tpl", tpls.kind[1].Name())
+	assert.Empty(t, tpls.group)
+}
+
+func Test_loadTemplates_invalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, groupVersionInfoTemplate), []byte("{{ .Version "), 0o600))
+
+	_, err := loadTemplates(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error parsing template: template: group_version_into.go.tpl:1")
+}
+
+func Test_loadTemplates_unknown(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deepcopy.go.tpl"), []byte("package v1"), 0o600))
+
+	_, err := loadTemplates(dir)
+	require.ErrorIs(t, err, ErrUnknownTemplate)
+}
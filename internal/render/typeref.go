@@ -0,0 +1,61 @@
+package render
+
+import "strings"
+
+// typeRef describes the go type expression of a generated field.
+type typeRef struct {
+	// Pointer is true for *T.
+	Pointer bool
+	// Slice is true for []T and []*T.
+	Slice bool
+	// Map is true for map[string]T and map[string]*T.
+	Map bool
+	// ElemPointer is true for []*T and map[string]*T.
+	ElemPointer bool
+	// Name is the remaining element type T.
+	Name string
+}
+
+func parseTypeRef(t string) typeRef {
+	var ref typeRef
+	switch {
+	case strings.HasPrefix(t, "*"):
+		ref.Pointer = true
+		t = t[1:]
+	case strings.HasPrefix(t, "[]"):
+		ref.Slice = true
+		t = t[2:]
+	case strings.HasPrefix(t, "map[string]"):
+		ref.Map = true
+		t = strings.TrimPrefix(t, "map[string]")
+	default:
+	}
+	if (ref.Slice || ref.Map) && strings.HasPrefix(t, "*") {
+		ref.ElemPointer = true
+		t = t[1:]
+	}
+	ref.Name = t
+	return ref
+}
+
+// isLocal checks if the element type is a type of the generated package.
+func (r typeRef) isLocal() bool {
+	return r.Name != "" && !strings.ContainsAny(r.Name, ".[]*") && !isBuiltin(r.Name)
+}
+
+// isNilable checks if the type can be nil. For types of other packages it is evaluated at runtime.
+func (r typeRef) isNilable() bool {
+	return r.Pointer || r.Slice || r.Map || r.Name == "any" || strings.Contains(r.Name, ".")
+}
+
+func isBuiltin(name string) bool {
+	switch name {
+	case "string", "bool", "byte", "any",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		return true
+	default:
+		return false
+	}
+}
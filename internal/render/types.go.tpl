@@ -3,7 +3,7 @@
 
 // Code generated by {{ .AppName }}. DO NOT EDIT.
 
-package {{ .Version }}
+package {{ .Package }}
 
 import (
 {{- range .Imports }}
@@ -25,6 +25,9 @@ type {{ .List }} struct {
 // +kubebuilder:object:root=true
 
 {{ if .Root.Description }}// {{ .Root.Description  }}{{ end }}
+{{- range .Root.Markers }}
+// +{{ . }}
+{{- end }}
 type {{ .Kind }} struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -32,7 +35,15 @@ type {{ .Kind }} struct {
 	{{- if .Description }}
 	// {{ .Description }}
 	{{- end }}
-	{{ .Name }} {{ .Type }} `json:"{{ .JSONTag }},omitempty"`
+	{{- if .Required }}
+	// +required
+	{{- else }}
+	// +optional
+	{{- end }}
+	{{- range .Markers }}
+	// +{{ . }}
+	{{- end }}
+	{{ .Name }} {{ .Type }} `json:"{{ .JSONTag }}{{ if not .Required }},omitempty{{ end }}"`
 	{{- end }}
 }
 
@@ -42,27 +53,74 @@ type {{ $struct.Name }} struct {
 	{{- if $field.Description }}
 	// {{ $field.Description }}
 	{{- end }}
+	{{- if $field.Required }}
+	// +required
+	{{- else }}
+	// +optional
+	{{- end }}
+	{{- range $field.Markers }}
+	// +{{ . }}
+	{{- end }}
 	{{- if $field.SkipDeepEqual }}
 	// +deepequal-gen=false
 	{{- end }}
-	{{ $field.Name }} {{ $field.Type }} `json:"{{ $field.JSONTag }},omitempty"`
+	{{ $field.Name }} {{ $field.Type }} `json:"{{ $field.JSONTag }}{{ if not $field.Required }},omitempty{{ end }}"`
     {{- end }}
 }
 
 {{ end }}
 
-{{- range $_, $struct := .Structs }}
-{{- range $_, $field :=  $struct.Fields }}
-{{- if $field.Enums }}
-// {{ $field.EnumName }} represents an enumeration for {{ $field.Name }}
-type {{ $field.EnumName }} {{ $field.EnumType }}
+{{- range $_, $enum := .Enums }}
+// {{ $enum.Name }} represents an enumeration for {{ $enum.Field }}
+type {{ $enum.Name }} {{ $enum.Type }}
 
-var (
-{{- range $field.Enums }}
-	// {{ .Name }} {{ $field.Name }} enum value {{ .Value }}
-	{{ .Name }} {{ $field.EnumName }} = {{ .Value }}
+const (
+{{- range $enum.Values }}
+	// {{ .Name }} {{ $enum.Field }} enum value {{ .Value }}
+	{{ .Name }} {{ $enum.Name }} = {{ .Value }}
 {{- end }}
 )
-{{ end }}
+
+// All{{ $enum.Name }}Values returns all values of the {{ $enum.Name }} enumeration.
+func All{{ $enum.Name }}Values() []{{ $enum.Name }} {
+	return []{{ $enum.Name }}{
+	{{- range $enum.Values }}
+		{{ .Name }},
+	{{- end }}
+	}
+}
+
+// IsValid returns true if the value is one of the {{ $enum.Name }} enumeration.
+func (e {{ $enum.Name }}) IsValid() bool {
+	switch e {
+	case {{ range $i, $v := $enum.Values }}{{ if $i }}, {{ end }}{{ $v.Name }}{{ end }}:
+		return true
+	default:
+		return false
+	}
+}
+{{- if eq $enum.Type "string" }}
+
+// String returns the value of the {{ $enum.Name }}.
+func (e {{ $enum.Name }}) String() string {
+	return string(e)
+}
 {{- end }}
-{{- end }}
\ No newline at end of file
+
+// UnmarshalJSON unmarshals the value and checks if it is one of the {{ $enum.Name }} enumeration.
+// A null value is ignored.
+func (e *{{ $enum.Name }}) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value {{ $enum.Type }}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if !{{ $enum.Name }}(value).IsValid() {
+		return fmt.Errorf("invalid {{ $enum.Name }} value {{ if eq $enum.Type "string" }}%q{{ else }}%v{{ end }}, must be one of %v", value, All{{ $enum.Name }}Values())
+	}
+	*e = {{ $enum.Name }}(value)
+	return nil
+}
+{{ end }}
\ No newline at end of file
@@ -0,0 +1,395 @@
+// Package generator generates Go API types from CustomResourceDefinitions.
+//
+// A Generator is configured with functional options and returns the generated files in memory:
+//
+//	g := generator.New(generator.WithVersions(generator.VersionsServed), generator.WithCELValidation())
+//	files, err := g.GenerateFromFiles(ctx, "config/crd/bases")
+//
+// A Generator holds no global state, several generators can be used concurrently.
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/bakito/crd-gen/internal/openapi"
+	"github.com/bakito/crd-gen/internal/render"
+)
+
+// PointerMode defines which struct fields are generated as pointers.
+type PointerMode = openapi.PointerMode
+
+const (
+	// PointerNone generates no pointer fields.
+	PointerNone = openapi.PointerNone
+	// PointerAll generates all struct fields as pointers.
+	PointerAll = openapi.PointerAll
+	// PointerOptional generates optional scalar and struct fields as pointers.
+	PointerOptional = openapi.PointerOptional
+)
+
+const (
+	// VersionsAll selects all versions of a CRD.
+	VersionsAll = openapi.VersionsAll
+	// VersionsServed selects the served versions of a CRD.
+	VersionsServed = openapi.VersionsServed
+	// VersionsStorage selects the storage version of a CRD.
+	VersionsStorage = openapi.VersionsStorage
+)
+
+// NamingStrategy defines how the generated structs and enum types are named.
+type NamingStrategy = openapi.NamingStrategy
+
+const (
+	// NamingShortest uses the shortest unique name, prefixed with the kind and parent properties on collisions.
+	// The names depend on the order the CRDs and properties are processed in.
+	NamingShortest = openapi.NamingShortest
+	// NamingPath prefixes the names with the kind and the full schema path, e.g. WidgetSpecTemplateSpec.
+	NamingPath = openapi.NamingPath
+	// NamingKind prefixes the names with the kind, e.g. WidgetTemplate.
+	NamingKind = openapi.NamingKind
+)
+
+// Layout defines the directories the group version packages are written to.
+type Layout = render.Layout
+
+const (
+	// LayoutDefault writes each version to <target>/<version>. If the packages have different groups,
+	// each group is written to its own <target>/<group-short>/<version> directory.
+	LayoutDefault = render.LayoutDefault
+	// LayoutGroupVersion writes each package to <target>/<group-short>/<version>, also for a single group.
+	LayoutGroupVersion = render.LayoutGroupVersion
+	// LayoutFlat writes a single group version package directly into the target directory.
+	LayoutFlat = render.LayoutFlat
+)
+
+// ParseError is an error reading an input or parsing a CRD, with the input, CRD, version and schema path.
+type ParseError = openapi.ParseError
+
+var (
+	// ErrNoCRDFound is returned if the inputs contain no CRD matching the filters.
+	ErrNoCRDFound = openapi.ErrNoCRDFound
+	// ErrNoSchema is returned if a CRD version has no openAPIV3Schema.
+	ErrNoSchema = openapi.ErrNoSchema
+	// ErrVersionNotFound is returned if none of the desired versions is defined in a CRD.
+	ErrVersionNotFound = openapi.ErrVersionNotFound
+	// ErrUnsupportedType is returned if a property has a type that can not be mapped to a go type.
+	ErrUnsupportedType = openapi.ErrUnsupportedType
+	// ErrInvalidTypeOverride is returned if a type override is incomplete.
+	ErrInvalidTypeOverride = openapi.ErrInvalidTypeOverride
+	// ErrInvalidFieldName is returned if an explicit field name is not an exported go identifier.
+	ErrInvalidFieldName = openapi.ErrInvalidFieldName
+	// ErrInvalidNamingStrategy is returned if the naming strategy is unknown.
+	ErrInvalidNamingStrategy = openapi.ErrInvalidNamingStrategy
+	// ErrStructNameCollision is returned if several structs or enum types of a package get the same name
+	// with the path or kind naming strategy.
+	ErrStructNameCollision = openapi.ErrStructNameCollision
+	// ErrInvalidSource is returned if a template renders code that is not valid go.
+	ErrInvalidSource = render.ErrInvalidSource
+	// ErrUnknownTemplate is returned if the template directory contains a template that is neither
+	// a built-in template nor an additional kind or group template.
+	ErrUnknownTemplate = render.ErrUnknownTemplate
+	// ErrInvalidPackageName is returned if the package name is not a go identifier.
+	ErrInvalidPackageName = render.ErrInvalidPackageName
+	// ErrInvalidLayout is returned if the layout is unknown or can not hold all packages.
+	ErrInvalidLayout = render.ErrInvalidLayout
+	// ErrInvalidFileName is returned if the types file name pattern is not a go file name,
+	// or if several files are rendered to the same path.
+	ErrInvalidFileName = render.ErrInvalidFileName
+	// ErrStaleFiles is returned by Check if the generated files on disk differ from the generated files.
+	ErrStaleFiles = render.ErrStaleFiles
+)
+
+// SourceError is a syntax error in a generated file, with the template and the position of the error.
+type SourceError = render.SourceError
+
+// KnownType is an existing go type that is used instead of generating a struct,
+// if a schema matches the json structure of the type.
+type KnownType = openapi.KnownType
+
+// NewKnownType creates a known type of the go type T, imported with the given package alias.
+func NewKnownType[T any](alias string) KnownType {
+	return openapi.NewKnownType[T](alias)
+}
+
+// DefaultKnownTypes returns the registry of well known Kubernetes types.
+func DefaultKnownTypes() []KnownType {
+	return openapi.DefaultKnownTypes()
+}
+
+// TypeOverride replaces the generated type of a property, selected by schema path or struct name,
+// with an existing go type.
+type TypeOverride = openapi.TypeOverride
+
+// File is a generated file.
+type File struct {
+	// Path is the path of the file, located in the target directory.
+	Path string
+	// Content is the go source of the file.
+	Content []byte
+}
+
+// Generator generates Go API types from CRDs.
+type Generator struct {
+	parseOpts  openapi.Options
+	renderOpts render.Options
+	targetDir  string
+}
+
+// Option configures a Generator.
+type Option func(g *Generator)
+
+// New creates a new Generator. Without options, the storage version of each CRD is generated into the
+// current directory.
+func New(opts ...Option) *Generator {
+	g := &Generator{}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// WithTargetDir defines the directory the file paths are located in.
+func WithTargetDir(dir string) Option {
+	return func(g *Generator) {
+		g.targetDir = dir
+	}
+}
+
+// WithVersions selects the versions of each CRD: VersionsAll, VersionsServed, VersionsStorage or version names.
+func WithVersions(versions ...string) Option {
+	return func(g *Generator) {
+		g.parseOpts.Versions = append(g.parseOpts.Versions, versions...)
+	}
+}
+
+// WithPointerMode defines which struct fields are generated as pointers.
+func WithPointerMode(mode PointerMode) Option {
+	return func(g *Generator) {
+		g.parseOpts.Pointers = mode
+	}
+}
+
+// WithKinds selects the CRDs by kind.
+func WithKinds(kinds ...string) Option {
+	return func(g *Generator) {
+		g.parseOpts.Kinds = append(g.parseOpts.Kinds, kinds...)
+	}
+}
+
+// WithGroups selects the CRDs by API group.
+func WithGroups(groups ...string) Option {
+	return func(g *Generator) {
+		g.parseOpts.Groups = append(g.parseOpts.Groups, groups...)
+	}
+}
+
+// WithHelmTemplates renders the templates of Helm chart inputs with the helm binary and the given values files.
+func WithHelmTemplates(valuesFiles ...string) Option {
+	return func(g *Generator) {
+		g.parseOpts.HelmTemplates = true
+		g.parseOpts.HelmValues = append(g.parseOpts.HelmValues, valuesFiles...)
+	}
+}
+
+// WithKnownTypes adds types to the known types registry, e.g. WithKnownTypes(DefaultKnownTypes()...).
+// Without known types, structs are generated for all schemas.
+func WithKnownTypes(types ...KnownType) Option {
+	return func(g *Generator) {
+		g.parseOpts.KnownTypes = append(g.parseOpts.KnownTypes, types...)
+	}
+}
+
+// WithoutKnownTypes clears the known types registry, structs are generated for all schemas.
+func WithoutKnownTypes() Option {
+	return func(g *Generator) {
+		g.parseOpts.KnownTypes = nil
+	}
+}
+
+// WithTypeOverrides replaces the generated types of the selected properties, their schema is not generated.
+func WithTypeOverrides(overrides ...TypeOverride) Option {
+	return func(g *Generator) {
+		g.parseOpts.TypeOverrides = append(g.parseOpts.TypeOverrides, overrides...)
+	}
+}
+
+// WithInitialisms adds initialisms written in upper case in go names, e.g. OIDC.
+// The common initialisms of the go lint rules (ID, URL, API, TLS, ...) are always applied.
+func WithInitialisms(initialisms ...string) Option {
+	return func(g *Generator) {
+		g.parseOpts.Initialisms = append(g.parseOpts.Initialisms, initialisms...)
+	}
+}
+
+// WithFieldNames defines explicit go field names by schema path (spec.apiURL) or property name (apiURL).
+// A name for the schema path has precedence over a name for the property name.
+func WithFieldNames(fieldNames map[string]string) Option {
+	return func(g *Generator) {
+		if g.parseOpts.FieldNames == nil {
+			g.parseOpts.FieldNames = make(map[string]string)
+		}
+		maps.Copy(g.parseOpts.FieldNames, fieldNames)
+	}
+}
+
+// WithNamingStrategy defines how the generated structs and enum types are named.
+// NamingPath and NamingKind create names that do not depend on the other CRDs of a run.
+func WithNamingStrategy(naming NamingStrategy) Option {
+	return func(g *Generator) {
+		g.parseOpts.Naming = naming
+	}
+}
+
+// WithClientConfig defines the kubernetes client config used to read k8s:<name> inputs from a cluster.
+func WithClientConfig(config clientcmd.ClientConfig) Option {
+	return func(g *Generator) {
+		g.parseOpts.ClientConfig = config
+	}
+}
+
+// WithCELValidation generates ValidateCEL methods evaluating the x-kubernetes-validations rules.
+func WithCELValidation() Option {
+	return func(g *Generator) {
+		g.renderOpts.CELValidation = true
+	}
+}
+
+// WithConversion generates the conversion functions between the versions of a kind.
+// If the hub version is empty, the storage version is the hub.
+func WithConversion(hubVersion string) Option {
+	return func(g *Generator) {
+		g.renderOpts.Conversion = true
+		g.renderOpts.HubVersion = hubVersion
+	}
+}
+
+// WithApplyConfigurations generates the server-side apply configurations of the types into the
+// applyconfiguration directory of the target directory.
+func WithApplyConfigurations() Option {
+	return func(g *Generator) {
+		g.renderOpts.ApplyConfigurations = true
+	}
+}
+
+// WithClients generates the typed clientset, the listers and the informers of the types into the
+// clientset, listers and informers directories of the target directory.
+func WithClients() Option {
+	return func(g *Generator) {
+		g.renderOpts.Clients = true
+	}
+}
+
+// WithPackage defines the go package name of the generated packages. If not defined, the version is used.
+func WithPackage(name string) Option {
+	return func(g *Generator) {
+		g.renderOpts.Package = name
+	}
+}
+
+// WithLayout defines the directories the group version packages are written to.
+func WithLayout(layout Layout) Option {
+	return func(g *Generator) {
+		g.renderOpts.Layout = layout
+	}
+}
+
+// WithTypesFile defines the file name pattern of the types files with the placeholders {kind} (lower case kind),
+// {Kind} and {version}. Kinds with the same file name are combined, e.g. all kinds of a package with "types.go".
+func WithTypesFile(pattern string) Option {
+	return func(g *Generator) {
+		g.renderOpts.TypesFile = pattern
+	}
+}
+
+// WithTemplateDir defines a directory of user templates. The types.go.tpl and group_version_into.go.tpl templates
+// replace the built-in ones, the templates of the kind and group sub directories render additional files per kind
+// and per group version.
+func WithTemplateDir(dir string) Option {
+	return func(g *Generator) {
+		g.renderOpts.TemplateDir = dir
+	}
+}
+
+// WithImportPath defines the go import path of the target directory, used to import the hub packages
+// of conversions and the types of apply configurations and clients.
+// If not defined, it is evaluated from the go.mod file enclosing the target directory.
+func WithImportPath(importPath string) Option {
+	return func(g *Generator) {
+		g.renderOpts.ImportPath = importPath
+	}
+}
+
+// GenerateFromFiles generates the files of CRD inputs: files, directories, glob patterns, Helm charts,
+// http(s) URLs or k8s:<name> references.
+func (g *Generator) GenerateFromFiles(ctx context.Context, inputs ...string) ([]File, error) {
+	packages, err := openapi.Parse(ctx, inputs, g.parseOpts)
+	if err != nil {
+		return nil, err
+	}
+	return g.renderFiles(packages)
+}
+
+// GenerateFromBytes generates the files of single or multi-document CRD yaml or json data.
+func (g *Generator) GenerateFromBytes(ctx context.Context, data ...[]byte) ([]File, error) {
+	var defs []openapi.Definition
+	for i, d := range data {
+		input := fmt.Sprintf("data[%d]", i)
+		decoded, err := openapi.DecodeDefinitions(ctx, input, d)
+		if err != nil {
+			return nil, &ParseError{Input: input, Err: err}
+		}
+		defs = append(defs, decoded...)
+	}
+	return g.generate(defs)
+}
+
+// GenerateFromCRDs generates the files of CRD values.
+func (g *Generator) GenerateFromCRDs(_ context.Context, crds ...*apiv1.CustomResourceDefinition) ([]File, error) {
+	defs := make([]openapi.Definition, 0, len(crds))
+	for _, crd := range crds {
+		crd = crd.DeepCopy()
+		// Apply the same defaulting the Kubernetes API server does
+		apiv1.SetObjectDefaults_CustomResourceDefinition(crd)
+		defs = append(defs, openapi.Definition{Input: crd.Name, CRD: crd})
+	}
+	return g.generate(defs)
+}
+
+func (g *Generator) generate(defs []openapi.Definition) ([]File, error) {
+	packages, err := openapi.ParseDefinitions(defs, g.parseOpts)
+	if err != nil {
+		return nil, err
+	}
+	return g.renderFiles(packages)
+}
+
+func (g *Generator) renderFiles(packages []*openapi.CustomResources) ([]File, error) {
+	rendered, err := render.Render(packages, g.targetDir, g.renderOpts)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]File, 0, len(rendered))
+	for _, f := range rendered {
+		files = append(files, File{Path: f.Name, Content: []byte(f.Content)})
+	}
+	return files, nil
+}
+
+// Check compares the generated files byte for byte with the files on disk, without writing.
+// Files generated by crd-gen in the generated package directories that are not generated anymore are reported
+// as stale.
+// The unified diff of the differences is written to w, an error wrapping ErrStaleFiles is returned if any
+// file differs.
+func (g *Generator) Check(files []File, w io.Writer) error {
+	rendered := make([]render.File, 0, len(files))
+	for _, f := range files {
+		rendered = append(rendered, render.File{Name: f.Path, Content: string(f.Content)})
+	}
+	return render.CheckFiles(rendered, g.targetDir, w)
+}
@@ -0,0 +1,228 @@
+package generator_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/bakito/crd-gen/pkg/generator"
+)
+
+const testdata = "../../testdata"
+
+func fileNames(files []generator.File) []string {
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Path)
+	}
+	return names
+}
+
+func TestGenerateFromFiles(t *testing.T) {
+	g := generator.New(generator.WithTargetDir("apis"), generator.WithKinds("Gadget"))
+	files, err := g.GenerateFromFiles(t.Context(), filepath.Join(testdata, "bundle"))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		filepath.Join("apis", "v1", "types_gadget.go"),
+		filepath.Join("apis", "v1", "group_version_info.go"),
+		filepath.Join("apis", "v1", "zz_generated.defaults.go"),
+		filepath.Join("apis", "v1", "zz_generated.deepcopy.go"),
+	}, fileNames(files))
+	assert.NoDirExists(t, "apis")
+}
+
+func TestGenerateFromBytes(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdata, "conversion.testing.crd-gen.yaml"))
+	require.NoError(t, err)
+
+	g := generator.New(
+		generator.WithVersions(generator.VersionsAll),
+		generator.WithConversion(""),
+		generator.WithImportPath("example.com/apis"),
+	)
+	files, err := g.GenerateFromBytes(t.Context(), data)
+	require.NoError(t, err)
+
+	var conversion string
+	for _, f := range files {
+		if f.Path == filepath.Join("v1alpha1", "zz_generated.conversion.go") {
+			conversion = string(f.Content)
+		}
+	}
+	assert.Contains(t, conversion, `hub "example.com/apis/v1"`)
+}
+
+func TestGenerateApplyConfigurations(t *testing.T) {
+	g := generator.New(
+		generator.WithTargetDir("apis"),
+		generator.WithKinds("Gadget"),
+		generator.WithApplyConfigurations(),
+		generator.WithImportPath("example.com/apis"),
+	)
+	files, err := g.GenerateFromFiles(t.Context(), filepath.Join(testdata, "bundle"))
+	require.NoError(t, err)
+
+	assert.Subset(t, fileNames(files), []string{
+		filepath.Join("apis", "applyconfiguration", "v1", "gadget.go"),
+		filepath.Join("apis", "applyconfiguration", "v1", "internal.go"),
+	})
+	for _, f := range files {
+		if f.Path == filepath.Join("apis", "applyconfiguration", "v1", "gadget.go") {
+			assert.Contains(t, string(f.Content), `api "example.com/apis/v1"`)
+		}
+	}
+}
+
+func TestGenerateClients(t *testing.T) {
+	g := generator.New(
+		generator.WithTargetDir("apis"),
+		generator.WithKinds("Gadget"),
+		generator.WithClients(),
+		generator.WithImportPath("example.com/apis"),
+	)
+	files, err := g.GenerateFromFiles(t.Context(), filepath.Join(testdata, "bundle"))
+	require.NoError(t, err)
+
+	assert.Subset(t, fileNames(files), []string{
+		filepath.Join("apis", "clientset", "versioned", "clientset.go"),
+		filepath.Join("apis", "clientset", "versioned", "scheme", "register.go"),
+		filepath.Join("apis", "listers", "bundle", "v1", "gadget.go"),
+		filepath.Join("apis", "informers", "externalversions", "factory.go"),
+	})
+}
+
+func TestGenerateWithTemplateDir(t *testing.T) {
+	g := generator.New(
+		generator.WithKinds("Gadget"),
+		generator.WithTemplateDir(filepath.Join(testdata, "templates")),
+	)
+	files, err := g.GenerateFromFiles(t.Context(), filepath.Join(testdata, "bundle"))
+	require.NoError(t, err)
+
+	assert.Subset(t, fileNames(files), []string{
+		filepath.Join("v1", "accessors_gadget.go"),
+		filepath.Join("v1", "kinds.go"),
+	})
+	for _, f := range files {
+		if f.Path == filepath.Join("v1", "group_version_info.go") {
+			assert.Contains(t, string(f.Content), "runtime.NewSchemeBuilder(addKnownTypes)")
+		}
+	}
+}
+
+func TestGenerateWithLayout(t *testing.T) {
+	g := generator.New(
+		generator.WithTargetDir("apis"),
+		generator.WithLayout(generator.LayoutFlat),
+		generator.WithPackage("bundle"),
+		generator.WithTypesFile("types.go"),
+	)
+	files, err := g.GenerateFromFiles(t.Context(), filepath.Join(testdata, "bundle"))
+	require.NoError(t, err)
+
+	assert.Contains(t, fileNames(files), filepath.Join("apis", "types.go"))
+	for _, f := range files {
+		assert.Equal(t, "apis", filepath.Dir(f.Path))
+		assert.Contains(t, string(f.Content), "package bundle")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	g := generator.New(generator.WithTargetDir(dir), generator.WithKinds("Gadget"))
+	files, err := g.GenerateFromFiles(t.Context(), filepath.Join(testdata, "bundle"))
+	require.NoError(t, err)
+
+	w := new(bytes.Buffer)
+	require.ErrorIs(t, g.Check(files, w), generator.ErrStaleFiles)
+	assert.Contains(t, w.String(), "+++ "+files[0].Path)
+
+	for _, f := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(f.Path), 0o755))
+		require.NoError(t, os.WriteFile(f.Path, f.Content, 0o600))
+	}
+	w.Reset()
+	require.NoError(t, g.Check(files, w))
+	assert.Empty(t, w.String())
+}
+
+func TestGenerateFromCRDs(t *testing.T) {
+	crd := readCRD(t, filepath.Join(testdata, "no-listkind.testing.crd-gen.yaml"))
+
+	files, err := generator.New().GenerateFromCRDs(t.Context(), crd)
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	assert.Empty(t, crd.Spec.Names.ListKind, "the input CRD must not be modified")
+
+	for _, f := range files {
+		if f.Path == filepath.Join("v1", "group_version_info.go") {
+			assert.Contains(t, string(f.Content), "&NoListKind{}, &NoListKindList{}")
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := generator.New(generator.WithGroups("unknown.crd-gen")).
+		GenerateFromCRDs(t.Context(), readCRD(t, filepath.Join(testdata, "no-listkind.testing.crd-gen.yaml")))
+	require.ErrorIs(t, err, generator.ErrNoCRDFound)
+
+	_, err = generator.New(generator.WithVersions(generator.VersionsAll)).
+		GenerateFromFiles(t.Context(), filepath.Join(testdata, "invalid", "broken.testing.crd-gen.yaml"))
+	require.ErrorIs(t, err, generator.ErrUnsupportedType)
+	var parseErr *generator.ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "brokens.testing.crd-gen", parseErr.CRD)
+}
+
+func TestGenerateWithTypeOverrides(t *testing.T) {
+	crd := readCRD(t, filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"))
+	files, err := generator.New(generator.WithTypeOverrides(generator.TypeOverride{
+		Path:   "spec.objectField",
+		Type:   "json.RawMessage",
+		Import: "encoding/json",
+	})).GenerateFromCRDs(t.Context(), crd)
+	require.NoError(t, err)
+	for _, f := range files {
+		if f.Path == filepath.Join("v1", "types_allcase.go") {
+			assert.Contains(t, string(f.Content), "ObjectField json.RawMessage")
+			assert.NotContains(t, string(f.Content), "type ObjectField struct")
+		}
+	}
+
+	_, err = generator.New(generator.WithTypeOverrides(generator.TypeOverride{Path: "spec.objectField"})).
+		GenerateFromCRDs(t.Context(), crd)
+	require.ErrorIs(t, err, generator.ErrInvalidTypeOverride)
+}
+
+func TestGenerateConcurrently(t *testing.T) {
+	crd := readCRD(t, filepath.Join(testdata, "all-cases.testing.crd-gen.yaml"))
+	expected, err := generator.New().GenerateFromCRDs(t.Context(), crd)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			files, err := generator.New().GenerateFromCRDs(t.Context(), crd)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, files)
+		})
+	}
+	wg.Wait()
+}
+
+func readCRD(t *testing.T, file string) *apiv1.CustomResourceDefinition {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	crd := &apiv1.CustomResourceDefinition{}
+	require.NoError(t, yaml.Unmarshal(data, crd))
+	return crd
+}
@@ -0,0 +1,2 @@
+// {{ .AppName }}
+package {{ .Version }}